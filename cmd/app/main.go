@@ -2,15 +2,28 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shlmvgleb/em-task/cmd/docs"
 	"github.com/shlmvgleb/em-task/internal/config"
 	"github.com/shlmvgleb/em-task/internal/database"
 	"github.com/shlmvgleb/em-task/internal/handlers"
+	"github.com/shlmvgleb/em-task/internal/models"
 	repositories "github.com/shlmvgleb/em-task/internal/repositories/postgres"
 	"github.com/shlmvgleb/em-task/internal/services"
+	"github.com/shlmvgleb/em-task/internal/services/songdetailsfake"
+	"github.com/shlmvgleb/em-task/pkg/cursor"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/shlmvgleb/em-task/pkg/requests"
 	log "github.com/sirupsen/logrus"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -21,38 +34,129 @@ const (
 	ProdEnv = "production"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	config := config.ReadFromEnv()
 	loggerSetup(config)
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	db, err := database.New(config.Postgres, ctx)
 	if err != nil {
 		log.Fatalf("error while connecting to database: %s", err)
 	}
 
 	songRepo := repositories.NewPostgresSongRepo(db)
+	reindexed, err := songRepo.SyncSearchConfig(ctx)
+	if err != nil {
+		log.Fatalf("error while syncing song search config: %s", err)
+	}
+
+	if reindexed > 0 {
+		log.Infof("Reindexed %d songs for search language %s", reindexed, config.Postgres.SearchLanguage)
+	}
+
+	enrichmentJobRepo := repositories.NewPostgresEnrichmentJobRepo(db)
+	artistRepo := repositories.NewPostgresArtistRepo(db)
+	albumRepo := repositories.NewPostgresAlbumRepo(db)
+	playlistRepo := repositories.NewPostgresPlaylistRepo(db)
+	genreRepo := repositories.NewPostgresGenreRepo(db)
+	tagRepo := repositories.NewPostgresTagRepo(db)
+	verseRepo := repositories.NewPostgresVerseRepo(db)
+	lyricsRepo := repositories.NewPostgresLyricsRepo(db)
+	revisionRepo := repositories.NewPostgresSongRevisionRepo(db)
+	idempotencyRepo := repositories.NewPostgresIdempotencyRepo(db)
+
+	songService := services.NewSongService(songRepo, verseRepo, cursor.NewSigner(cursorSecret(config)))
+	artistService := services.NewArtistService(artistRepo, songRepo)
+	albumService := services.NewAlbumService(albumRepo)
+	playlistService := services.NewPlaylistService(playlistRepo)
+	genreService := services.NewGenreService(genreRepo)
+	tagService := services.NewTagService(tagRepo)
+	verseService := services.NewVerseService(verseRepo)
+	lyricsService := services.NewLyricsService(songRepo, lyricsRepo)
+	revisionService := services.NewRevisionService(revisionRepo)
+	songDetailsApiUrl := config.SongDetailsApi.Url
+	if songDetailsApiUrl == "" && config.AppEnv == DevEnv {
+		fake := songdetailsfake.NewServer()
+		defer fake.Close()
+
+		songDetailsApiUrl = fake.URL
+		log.Warnf("SONG_DETAILS_API_URL is not set, using fake song details api: %s", fake.URL)
+	}
+
+	songDetailsApiClient := requests.NewClient(&http.Client{}, requests.ClientConfig{
+		Timeout:                 config.SongDetailsApi.Timeout,
+		MaxRetries:              config.SongDetailsApi.MaxRetries,
+		RetryBaseDelay:          config.SongDetailsApi.RetryBaseDelay,
+		RetryMaxDelay:           config.SongDetailsApi.RetryMaxDelay,
+		BreakerFailureThreshold: config.SongDetailsApi.BreakerFailureThreshold,
+		BreakerOpenTimeout:      config.SongDetailsApi.BreakerOpenTimeout,
+	})
+	songDetailsApiService := services.NewSongDetailsHttpApiService(songDetailsApiClient, songDetailsApiUrl)
+
+	enrichmentService := services.NewEnrichmentService(
+		enrichmentJobRepo,
+		songRepo,
+		songDetailsApiService,
+		services.EnrichmentConfig{
+			Workers:        config.Enrichment.Workers,
+			PollInterval:   config.Enrichment.PollInterval,
+			LockTimeout:    config.Enrichment.LockTimeout,
+			MaxAttempts:    config.Enrichment.MaxAttempts,
+			RetryBaseDelay: config.Enrichment.RetryBaseDelay,
+			RetryMaxDelay:  config.Enrichment.RetryMaxDelay,
+		},
+	)
+	waitEnrichmentWorkers := enrichmentService.StartWorkers(ctx)
+
+	trashService := services.NewTrashService(songRepo, services.TrashConfig{
+		Retention:     config.Trash.Retention,
+		PurgeInterval: config.Trash.PurgeInterval,
+	})
+	waitTrashPurger := trashService.StartPurger(ctx)
 
-	songService := services.NewSongService(songRepo)
-	songDetailsApiService := services.NewSongDetailsMockApiService()
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo, services.IdempotencyConfig{
+		KeyTTL:        config.Idempotency.KeyTTL,
+		LockTimeout:   config.Idempotency.LockTimeout,
+		PurgeInterval: config.Idempotency.PurgeInterval,
+	})
+	waitIdempotencyPurger := idempotencyService.StartPurger(ctx)
 
 	cntrl := handlers.NewController(
 		songService,
-		songDetailsApiService,
+		enrichmentService,
+		artistService,
+		albumService,
+		playlistService,
+		genreService,
+		tagService,
+		verseService,
+		lyricsService,
+		revisionService,
+		trashService,
 	)
 
-	err = startServer(config, cntrl)
+	err = startServer(ctx, config, cntrl, idempotencyService)
 	if err != nil {
 		log.Fatalf("server is abruptly closed: %s", err)
 	}
+
+	waitEnrichmentWorkers()
+	waitTrashPurger()
+	waitIdempotencyPurger()
+	log.Infoln("Server gracefully stopped")
 }
 
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PATCH, DELETE")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Actor, If-Match, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Location, Idempotent-Replayed")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -63,6 +167,49 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
+// ActorMiddleware передает автора правок из заголовка X-Actor в контекст запроса, он записывается в ревизии песен
+func ActorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if actor := strings.TrimSpace(c.GetHeader("X-Actor")); actor != "" {
+			c.Request = c.Request.WithContext(models.WithActor(c.Request.Context(), actor))
+		}
+
+		c.Next()
+	}
+}
+
+// IfMatchMiddleware отклоняет запрос без заголовка If-Match, если required. Сама версия проверяется при записи
+func IfMatchMiddleware(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required && c.GetHeader("If-Match") == "" {
+			exceptions.IfMatchRequiredError(c)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// cursorSecret возвращает секрет подписи курсоров. В development без заданного секрета генерируется случайный,
+// и курсоры, выданные до перезапуска, становятся недействительными.
+func cursorSecret(config *config.AppConfig) []byte {
+	if config.Pagination.CursorSecret != "" {
+		return []byte(config.Pagination.CursorSecret)
+	}
+
+	if config.AppEnv != DevEnv {
+		log.Fatal("PAGINATION_CURSOR_SECRET is not set")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("failed to generate cursor secret: %s", err)
+	}
+
+	log.Warn("PAGINATION_CURSOR_SECRET is not set, using random secret")
+	return secret
+}
+
 func loggerSetup(config *config.AppConfig) {
 	log.SetFormatter(&log.JSONFormatter{
 		PrettyPrint:      true,
@@ -78,7 +225,12 @@ func loggerSetup(config *config.AppConfig) {
 	}
 }
 
-func startServer(config *config.AppConfig, cntrl *handlers.Controller) error {
+func startServer(
+	ctx context.Context,
+	config *config.AppConfig,
+	cntrl *handlers.Controller,
+	idempotencyService *services.IdempotencyService,
+) error {
 	if config.AppEnv == DevEnv {
 		gin.SetMode("debug")
 	}
@@ -89,22 +241,119 @@ func startServer(config *config.AppConfig, cntrl *handlers.Controller) error {
 
 	engine := gin.Default()
 	engine.Use(CORSMiddleware())
+	engine.Use(ActorMiddleware())
 
 	docs.SwaggerInfo.BasePath = "/api/v1"
 	v1 := engine.Group("/api/v1")
 	{
+		ifMatch := IfMatchMiddleware(config.Concurrency.RequireIfMatch)
+		idempotent := handlers.IdempotencyMiddleware(idempotencyService)
+
 		sg := v1.Group("/songs")
 		{
-			sg.POST("/", cntrl.AddSong)
-			sg.PATCH("/", cntrl.UpdateSong)
-			sg.DELETE("/:id", cntrl.DeleteSong)
+			sg.POST("/", idempotent, cntrl.AddSong)
+			sg.PATCH("/", ifMatch, cntrl.UpdateSong)
+			sg.PATCH("/:id", ifMatch, cntrl.PatchSong)
+			sg.PUT("/:id", ifMatch, cntrl.ReplaceSong)
+			sg.DELETE("/:id", ifMatch, cntrl.DeleteSong)
 			sg.GET("/", cntrl.GetSongsWithPagination)
-			sg.GET("/:id", cntrl.GetSongByIdWithVersePagination)
+			sg.GET("/suggest", cntrl.GetSongSuggestions)
+			sg.GET("/trash", cntrl.GetTrashWithPagination)
+			sg.GET("/search/lyrics", cntrl.SearchSongLyrics)
+			sg.GET("/:id", cntrl.GetSongById)
+			sg.GET("/paginated/:id", cntrl.GetSongByIdWithVersePagination)
+			sg.GET("/:id/enrichment", cntrl.GetSongEnrichment)
+			sg.POST("/:id/restore", cntrl.RestoreSongFromTrash)
+			sg.PUT("/:id/genres", cntrl.SetSongGenres)
+			sg.PUT("/:id/tags", cntrl.SetSongTags)
+			sg.GET("/:id/verses", cntrl.GetSongVerses)
+			sg.POST("/:id/verses", cntrl.AddVerse)
+			sg.PUT("/:id/verses/:verse_id", cntrl.UpdateVerse)
+			sg.PATCH("/:id/verses/:verse_id", cntrl.MoveVerse)
+			sg.DELETE("/:id/verses/:verse_id", cntrl.DeleteVerse)
+			sg.GET("/:id/lyrics", cntrl.GetSongLyrics)
+			sg.PUT("/:id/lyrics", cntrl.UploadSongLyrics)
+			sg.GET("/:id/lyrics/variants", cntrl.GetLyricsVariants)
+			sg.PUT("/:id/lyrics/variants/:lang", cntrl.SaveLyricsVariant)
+			sg.DELETE("/:id/lyrics/variants/:lang", cntrl.DeleteLyricsVariant)
+			sg.GET("/:id/revisions", cntrl.GetSongRevisions)
+			sg.GET("/:id/revisions/:rev", cntrl.GetSongRevision)
+			sg.POST("/:id/revisions/:rev/restore", ifMatch, cntrl.RestoreSongRevision)
+		}
+
+		ag := v1.Group("/artists")
+		{
+			ag.POST("/", cntrl.AddArtist)
+			ag.GET("/", cntrl.GetArtistsWithPagination)
+			ag.GET("/:id", cntrl.GetArtistById)
+			ag.PATCH("/:id", cntrl.UpdateArtist)
+			ag.DELETE("/:id", cntrl.DeleteArtist)
+			ag.GET("/:id/songs", cntrl.GetArtistSongsWithPagination)
+		}
+
+		alg := v1.Group("/albums")
+		{
+			alg.POST("/", cntrl.AddAlbum)
+			alg.GET("/", cntrl.GetAlbumsWithPagination)
+			alg.GET("/:id", cntrl.GetAlbumById)
+			alg.PATCH("/:id", cntrl.UpdateAlbum)
+			alg.DELETE("/:id", cntrl.DeleteAlbum)
+			alg.PUT("/:id/tracks/:song_id", cntrl.SetAlbumTrack)
+			alg.DELETE("/:id/tracks/:song_id", cntrl.RemoveAlbumTrack)
+		}
+
+		pg := v1.Group("/playlists")
+		{
+			pg.POST("/", cntrl.AddPlaylist)
+			pg.GET("/", cntrl.GetPlaylistsWithPagination)
+			pg.GET("/:id", cntrl.GetPlaylistById)
+			pg.PATCH("/:id", cntrl.RenamePlaylist)
+			pg.DELETE("/:id", cntrl.DeletePlaylist)
+			pg.POST("/:id/entries", cntrl.AddPlaylistEntry)
+			pg.PATCH("/:id/entries/:entry_id", cntrl.MovePlaylistEntry)
+			pg.DELETE("/:id/entries/:entry_id", cntrl.RemovePlaylistEntry)
+		}
+
+		gg := v1.Group("/genres")
+		{
+			gg.POST("/", cntrl.AddGenre)
+			gg.GET("/", cntrl.GetGenres)
+			gg.GET("/:id", cntrl.GetGenreById)
+			gg.PUT("/:id", cntrl.UpdateGenre)
+			gg.DELETE("/:id", cntrl.DeleteGenre)
+		}
+
+		tg := v1.Group("/tags")
+		{
+			tg.POST("/", cntrl.AddTag)
+			tg.GET("/", cntrl.GetTagsWithPagination)
+			tg.DELETE("/:id", cntrl.DeleteTag)
 		}
 	}
 
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%v", config.Port),
+		Handler: engine,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("server shutdown error: %s", err)
+		}
+	}()
+
 	log.Infof("Server listening on port: %d", config.Port)
-	return engine.Run(fmt.Sprintf(":%v", config.Port))
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
 }
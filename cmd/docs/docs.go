@@ -15,57 +15,42 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/songs": {
+        "/albums": {
             "get": {
-                "description": "Возвращает информацию о песне по указанному ID с поддержкой пагинации (если она нужна).",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает список альбомов без треклистов, новые релизы первыми",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Получение всех песен с пагинацией",
+                "summary": "Получение всех альбомов с пагинацией",
                 "parameters": [
                     {
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "Страница",
+                        "description": "Страница, по умолчанию 1",
                         "name": "page",
                         "in": "query"
                     },
                     {
+                        "maximum": 100,
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "Количество элементов",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
                         "name": "limit",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Полнотекстовый поиск по всем полям сущности Song",
-                        "name": "search_query",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешный ответ, песня найдена",
+                        "description": "Успешный ответ",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/services.SongsWithPagination"
-                            }
+                            "$ref": "#/definitions/services.AlbumsWithPagination"
                         }
                     },
                     "400": {
-                        "description": "Неверный запрос, ID песни не предоставлен или некорректен",
-                        "schema": {
-                            "$ref": "#/definitions/exceptions.Error"
-                        }
-                    },
-                    "404": {
-                        "description": "Песня с предоставленным ID не найдена",
+                        "description": "Неверный запрос, некорректные параметры пагинации",
                         "schema": {
                             "$ref": "#/definitions/exceptions.Error"
                         }
@@ -79,7 +64,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Создает новую запись о песне",
+                "description": "Создает новый альбом, исполнитель находится по имени или создается",
                 "consumes": [
                     "application/json"
                 ],
@@ -87,25 +72,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Добавление новой песни",
+                "summary": "Добавление альбома",
                 "parameters": [
                     {
-                        "description": "Данные песни для добавления",
-                        "name": "song",
+                        "description": "Данные альбома",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.AddSongPayload"
+                            "$ref": "#/definitions/handlers.AlbumPayload"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Песня успешно добавлена",
+                        "description": "Альбом успешно добавлен",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/models.Album"
                         }
                     },
                     "400": {
@@ -121,35 +106,71 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Обновляет запись о песне",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/albums/{id}": {
+            "get": {
+                "description": "Возвращает альбом с треками, упорядоченными по номеру диска и трека",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Обновление данных песни",
+                "summary": "Получение альбома по ID",
                 "parameters": [
                     {
-                        "description": "Данные песни для обновления",
-                        "name": "song",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "ID альбома",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Альбом успешно найден",
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "$ref": "#/definitions/models.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID альбома не предоставлен или некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Альбом с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
                         }
                     }
+                }
+            },
+            "delete": {
+                "description": "Удаляет альбом по ID, песни альбома остаются в библиотеке",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Удаление альбома",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID альбома",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Песня успешно обновлена",
+                    "204": {
+                        "description": "Альбом успешно удален",
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "type": "object"
                         }
                     },
                     "400": {
@@ -158,6 +179,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/exceptions.Error"
                         }
                     },
+                    "404": {
+                        "description": "Альбом с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -165,11 +192,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/songs/paginated/{id}": {
-            "get": {
-                "description": "Возвращает информацию о песне по указанному ID с поддержкой пагинации (если она нужна).",
+            },
+            "patch": {
+                "description": "Обновляет переданные поля альбома",
                 "consumes": [
                     "application/json"
                 ],
@@ -177,39 +202,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Получение песни по ID с пагинацией по куплетам",
+                "summary": "Обновление альбома",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Страница(порядковый номер куплета)",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Изменяемые поля альбома",
+                        "name": "album",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.AlbumPatch"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Песня успешно найдена",
+                        "description": "Альбом успешно обновлен",
                         "schema": {
-                            "$ref": "#/definitions/services.SongByIdWithVersePagination"
+                            "$ref": "#/definitions/models.Album"
                         }
                     },
                     "400": {
-                        "description": "Неверный запрос, ID песни не предоставлен или некорректен",
+                        "description": "Некорректный запрос, неправильный формат данных",
                         "schema": {
                             "$ref": "#/definitions/exceptions.Error"
                         }
                     },
                     "404": {
-                        "description": "Песня с предоставленным ID не найдена",
+                        "description": "Альбом с предоставленным ID не найден",
                         "schema": {
                             "$ref": "#/definitions/exceptions.Error"
                         }
@@ -223,9 +251,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/songs/{id}": {
-            "get": {
-                "description": "Возвращает информацию о песне по указанному ID",
+        "/albums/{id}/tracks/{song_id}": {
+            "put": {
+                "description": "Ставит песню на указанную позицию (диск и номер трека) альбома, песня может быть перенесена из другого альбома",
                 "consumes": [
                     "application/json"
                 ],
@@ -233,33 +261,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Получение песни по ID",
+                "summary": "Добавление песни в треклист альбома",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Позиция трека, disc_number по умолчанию 1",
+                        "name": "track",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AlbumTrackPayload"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Песня успешно найдена",
+                        "description": "Треклист успешно обновлен",
                         "schema": {
-                            "$ref": "#/definitions/services.SongByIdWithVersePagination"
+                            "$ref": "#/definitions/models.Album"
                         }
                     },
                     "400": {
-                        "description": "Неверный запрос, ID песни не предоставлен или некорректен",
+                        "description": "Некорректный запрос, неправильный формат данных",
                         "schema": {
                             "$ref": "#/definitions/exceptions.Error"
                         }
                     },
                     "404": {
-                        "description": "Песня с предоставленным ID не найдена",
+                        "description": "Альбом или песня не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Позиция трека уже занята",
                         "schema": {
                             "$ref": "#/definitions/exceptions.Error"
                         }
@@ -273,29 +323,33 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Удаляет песню по ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Убирает песню из альбома, сама песня остается в библиотеке",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Удаление песни",
+                "summary": "Удаление песни из треклиста альбома",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "204": {
-                        "description": "Песня успешно удалена",
+                        "description": "Песня убрана из альбома",
                         "schema": {
                             "type": "object"
                         }
@@ -306,6 +360,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/exceptions.Error"
                         }
                     },
+                    "404": {
+                        "description": "Песня не найдена в альбоме",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -314,70 +374,4130 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "exceptions.Error": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "message": {
-                    "type": "string"
-                }
-            }
         },
-        "handlers.AddSongPayload": {
-            "type": "object",
-            "properties": {
-                "group": {
-                    "type": "string"
-                },
-                "song": {
-                    "type": "string"
+        "/artists": {
+            "get": {
+                "description": "Возвращает список исполнителей, отсортированный по имени",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Получение всех исполнителей с пагинацией",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.ArtistsWithPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, некорректные параметры пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает нового исполнителя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Добавление исполнителя",
+                "parameters": [
+                    {
+                        "description": "Данные исполнителя",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ArtistPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Исполнитель успешно добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Исполнитель с таким именем уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
                 }
             }
         },
-        "models.Song": {
-            "description": "Структура, содержащая данные о песне, такие как группа, название песни, текст, дата выпуска и ссылка.",
-            "type": "object",
-            "properties": {
-                "group": {
-                    "type": "string"
-                },
+        "/artists/{id}": {
+            "get": {
+                "description": "Возвращает информацию об исполнителе по указанному ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Получение исполнителя по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Исполнитель успешно найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID исполнителя не предоставлен или некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет исполнителя по ID, если у него нет песен",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Удаление исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Исполнитель успешно удален",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "У исполнителя есть песни",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Обновляет имя исполнителя, имя исполнителя в его песнях обновляется вместе с ним",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Переименование исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные исполнителя",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ArtistPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Исполнитель успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Исполнитель с таким именем уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}/songs": {
+            "get": {
+                "description": "Возвращает песни исполнителя с пагинацией, отсортированные по дате выпуска",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Дискография исполнителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID исполнителя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.SongsWithPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID исполнителя или параметры пагинации некорректны",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Исполнитель с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "description": "Возвращает корневые жанры, поджанры вложены в children",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получение дерева жанров",
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Genre"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает новый жанр, parent_id делает его поджанром существующего жанра",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Добавление жанра",
+                "parameters": [
+                    {
+                        "description": "Данные жанра",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GenrePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Жанр успешно добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/models.Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных или родительский жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Жанр с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{id}": {
+            "get": {
+                "description": "Возвращает жанр без поджанров",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Получение жанра по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жанр успешно найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID жанра не предоставлен или некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Переименовывает жанр и/или переносит его под другой жанр, без parent_id жанр становится корневым",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Обновление жанра",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные жанра",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GenrePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жанр успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/models.Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, родительский жанр не найден или является поджанром",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Жанр с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет жанр по ID и снимает его со всех песен. Жанр с поджанрами удалить нельзя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Удаление жанра",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID жанра",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Жанр успешно удален",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "У жанра есть поджанры",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists": {
+            "get": {
+                "description": "Возвращает список плейлистов без песен, новые плейлисты первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Получение всех плейлистов с пагинацией",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.PlaylistsWithPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, некорректные параметры пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает новый пустой плейлист",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Создание плейлиста",
+                "parameters": [
+                    {
+                        "description": "Данные плейлиста",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlaylistPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Плейлист успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}": {
+            "get": {
+                "description": "Возвращает плейлист и страницу его записей в порядке позиций. Записи с песнями из корзины скрыты, позиции нумеруются подряд среди видимых записей",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Получение плейлиста с песнями",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист успешно найден",
+                        "schema": {
+                            "$ref": "#/definitions/services.PlaylistWithPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID плейлиста или параметры пагинации некорректны",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет плейлист вместе с его записями, песни остаются в библиотеке",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Удаление плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Плейлист успешно удален",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Меняет название плейлиста",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Переименование плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое название плейлиста",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlaylistPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист успешно переименован",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries": {
+            "post": {
+                "description": "Вставляет песню на позицию position (с 1), последующие записи сдвигаются. Без position песня добавляется в конец. Позиция считается среди видимых записей",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Добавление песни в плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Песня и позиция",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlaylistEntryPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Песня добавлена в плейлист",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист или песня не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/entries/{entry_id}": {
+            "delete": {
+                "description": "Удаляет запись из плейлиста, последующие записи сдвигаются, сама песня остается в библиотеке",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Удаление песни из плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID записи плейлиста",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Запись удалена",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист или запись не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Переносит запись на позицию position, остальные записи сдвигаются. Позиция за пределами плейлиста приводится к первой или последней. Позиция считается среди видимых записей, запись с песней из корзины считается не найденной",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Перемещение песни внутри плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID записи плейлиста",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая позиция",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlaylistEntryMovePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Запись перемещена",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист или запись не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs": {
+            "get": {
+                "description": "Возвращает информацию о песне по указанному ID с поддержкой пагинации (если она нужна).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение всех песен с пагинацией",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "page",
+                            "cursor"
+                        ],
+                        "type": "string",
+                        "description": "page - по номеру страницы (по умолчанию), cursor - курсором, current_page и pages_amount в этом режиме не заполняются",
+                        "name": "pagination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor или prev_cursor из предыдущего ответа, включает пагинацию курсором. Курсор действует только с теми же фильтрами и сортировкой, иначе 400",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "estimate",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "Подсчет total_items и pages_amount: exact - точный (по умолчанию), estimate - оценка планировщика, none - без подсчета",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Полнотекстовый поиск по названию, исполнителю и тексту (в порядке убывания веса), совпадения возвращаются в highlight",
+                        "name": "search_query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык полнотекстового поиска, тег BCP 47: запрос ищется в переводах на этот язык и в оригиналах с той же конфигурацией поиска",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Нечеткий поиск по названию и исполнителю с учетом опечаток, совпавшее поле и сходство возвращаются в match",
+                        "name": "fuzzy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Исполнитель, без учета регистра",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "prefix"
+                        ],
+                        "type": "string",
+                        "description": "exact - имя целиком (по умолчанию), prefix - начало имени",
+                        "name": "group_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название песни, без учета регистра",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "prefix"
+                        ],
+                        "type": "string",
+                        "description": "exact - название целиком (по умолчанию), prefix - начало названия",
+                        "name": "song_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Дата выпуска не раньше, YYYY-MM-DD",
+                        "name": "release_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Дата выпуска не позже, YYYY-MM-DD",
+                        "name": "release_date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Наличие ссылки на песню",
+                        "name": "has_link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Добавлена после, RFC3339 или YYYY-MM-DD",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Изменена после, RFC3339 или YYYY-MM-DD",
+                        "name": "updated_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Жанр, включая поджанры. Можно передать несколько раз",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "any - любой из жанров (по умолчанию), all - все жанры",
+                        "name": "genre_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Тег. Можно передать несколько раз",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "any - любой из тегов (по умолчанию), all - все теги",
+                        "name": "tag_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключи сортировки через запятую, '-' - по убыванию: release_date, created_at, updated_at, song, group, relevance (только с search_query), similarity (только с fuzzy). По умолчанию created_at, с fuzzy - -similarity",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Добавить в ответ количество найденных песен по жанрам и тегам",
+                        "name": "facets",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ, песня найдена",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/services.SongsWithPagination"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, некорректные параметры фильтрации или пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает новую запись о песне, детали (текст, ссылка, дата выпуска) заполняются асинхронно из внешнего API.\nУ исполнителя не может быть двух песен с одинаковым названием без учета регистра и пробелов.\nЗапрос с Idempotency-Key выполняется один раз: повтор с тем же ключом и телом в течение IDEMPOTENCY_KEY_TTL\nполучает сохраненный ответ с заголовком Idempotent-Replayed: true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Добавление новой песни",
+                "parameters": [
+                    {
+                        "description": "Данные песни для добавления",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddSongPayload"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности, до 255 печатных символов ASCII",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Песня успешно добавлена, обогащение поставлено в очередь",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "Путь добавленной песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных или Idempotency-Key",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Песня уже есть у исполнителя (ее путь в Location) или запрос с этим Idempotency-Key еще выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Путь существующей песни"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Тело запроса с Idempotency-Key больше 1 МБ",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Поля песни не прошли валидацию (нарушения в details) или Idempotency-Key использован с другим запросом",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Устарел, используйте PATCH и PUT /songs/{id}. Переносит в песню непустые поля, очистить поле нельзя. С заголовком If-Match песня обновляется, только если ее версия совпадает с переданным ETag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Обновление данных песни",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Данные песни для обновления",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Песня успешно обновлена",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "У исполнителя уже есть песня с таким названием, ее путь в Location",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена, версия не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Песня после изменения не прошла валидацию, нарушения перечислены в details",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "428": {
+                        "description": "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/paginated/{id}": {
+            "get": {
+                "description": "Возвращает песню по указанному ID и ее куплеты: страницу page по per_page куплетов или диапазон позиций verses (например, 2-4 или 3).\nТекст песни не возвращается, выбранные куплеты приходят списком verses с позициями.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение песни по ID с пагинацией по куплетам",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество куплетов на странице, по умолчанию 1",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Диапазон позиций куплетов, например 2-4; вместе с page и per_page не используется",
+                        "name": "verses",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык текста, тег BCP 47; заменяет Accept-Language. Куплеты перевода не имеют id",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предпочтительные языки текста",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня успешно найдена",
+                        "schema": {
+                            "$ref": "#/definitions/services.SongByIdWithVersePagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни или выбор куплетов некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или выбранные куплеты не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/search/lyrics": {
+            "get": {
+                "description": "Возвращает песни, в куплетах которых найден запрос, и номера этих куплетов. Номера куплетов можно передать как verses в /songs/paginated/{id}.\nС lang запрос разбирается конфигурацией поиска этого языка и ищется в переводах на этот язык (номера куплетов - куплеты перевода, см. lang в ответе)\nи в оригиналах песен без такого перевода, проиндексированных той же конфигурацией",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Поиск по текстам песен с точностью до куплета",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Запрос: слова, фраза в кавычках, -слово для исключения",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык поиска, тег BCP 47",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Все слова запроса должны быть в куплете не дальше стольких слов друг от друга (от 1 до 100), кавычки и исключения в этом режиме не поддерживаются",
+                        "name": "distance",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.LyricsMatchesWithPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, пустой запрос, некорректное расстояние или пагинация",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/suggest": {
+            "get": {
+                "description": "Возвращает песни, название или исполнитель которых начинаются с запроса или похожи на него с учетом опечаток. Совпадения по началу строки идут первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Подсказки для поиска по мере ввода",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Введенный текст",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество подсказок, по умолчанию 10, больше 50 уменьшается до 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SongSuggestion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, пустой текст или некорректный limit",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/trash": {
+            "get": {
+                "description": "Возвращает удаленные песни от последней удаленной, deleted_at - время удаления.\nПесни хранятся в корзине TRASH_RETENTION, затем удаляются окончательно",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение корзины песен",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.TrashedSongsWithPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, некорректные параметры пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}": {
+            "get": {
+                "description": "Возвращает информацию о песне по указанному ID. Текст песни выбирается по lang или Accept-Language:\nперевод на предпочтительном языке, иначе оригинал. Язык выбранного перевода - lyrics_lang и заголовок Content-Language.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение песни по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык текста, тег BCP 47; заменяет Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предпочтительные языки текста",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня успешно найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия песни для If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни не предоставлен или некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Заменяет все редактируемые поля песни. Непереданные поля очищаются, song и group обязательны,\nrelease_date - собственная дата песни YYYY-MM-DD, без нее дата берется из альбома.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Замена песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Редактируемые поля песни",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.SongDocument"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня заменена",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, тело не является JSON",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "У исполнителя уже есть песня с таким названием, ее путь в Location",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена, версия не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Неизвестные поля или поля песни не прошли валидацию (нарушения в details)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "428": {
+                        "description": "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Переносит песню в корзину. Из корзины песню можно восстановить, пока она не удалена окончательно по истечении TRASH_RETENTION",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удаление песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни, песня удаляется только в этой версии",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Песня успешно удалена",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена, версия не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "428": {
+                        "description": "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Применяет к редактируемым полям песни (SongDocument) JSON Merge Patch (application/merge-patch+json, RFC 7396,\napplication/json разбирается так же) или JSON Patch (application/json-patch+json, RFC 6902).\nnull в merge patch и remove в JSON Patch очищают поле. release_date в документе - собственная дата песни YYYY-MM-DD,\nnull - дата берется из альбома. Куплеты разбираются заново, только если изменился текст.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Частичное изменение песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch - объект с полями SongDocument, JSON Patch - массив операций",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня изменена",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный патч",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Операция test не совпала с песней или у исполнителя уже есть песня с таким названием (ее путь в Location)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена, версия не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый тип патча",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Патч не применяется к песне или песня после патча не прошла валидацию (нарушения в details)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "428": {
+                        "description": "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/enrichment": {
+            "get": {
+                "description": "Возвращает статус заполнения деталей песни из внешнего API и последнюю задачу обогащения",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Статус обогащения песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Статус обогащения",
+                        "schema": {
+                            "$ref": "#/definitions/services.SongEnrichment"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни не предоставлен или некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/genres": {
+            "put": {
+                "description": "Заменяет жанры песни переданным списком, жанры указываются по названию и должны существовать",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Установка жанров песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Названия жанров, пустой список снимает все жанры",
+                        "name": "genres",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SongGenresPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Жанры песни обновлены",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос или жанр не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics": {
+            "get": {
+                "description": "Возвращает текст песни в формате format: json - текст и строки с метками времени, lrc - файл LRC\n(только для синхронизированного текста), plain - текст без меток времени.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: json (по умолчанию), lrc или plain",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.SongLyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни или формат некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена или текст песни не синхронизирован",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Принимает файл LRC полем file формы multipart/form-data или телом запроса. Метки времени должны строго возрастать.\nТекст и куплеты песни собираются из строк файла, строки без текста разделяют куплеты.\nИзменение текста песни или ее куплетов другими запросами удаляет синхронизацию.",
+                "consumes": [
+                    "text/plain",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Загрузка синхронизированного текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл LRC",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст сохранен",
+                        "schema": {
+                            "$ref": "#/definitions/services.SongLyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, файл LRC не разобран",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics/variants": {
+            "get": {
+                "description": "Возвращает переводы и транслитерации текста песни, упорядоченные по языку",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение вариантов текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LyricsVariant"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни не предоставлен или некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics/variants/{lang}": {
+            "put": {
+                "description": "Сохраняет перевод или транслитерацию текста песни на языке lang, вариант на том же языке заменяется.\nТекст делится на куплеты пустыми строками, как и текст песни.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Добавление или изменение варианта текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык, тег BCP 47: en, ru-Latn",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Вариант текста, kind по умолчанию translation",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LyricsVariantPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вариант изменен",
+                        "schema": {
+                            "$ref": "#/definitions/models.LyricsVariant"
+                        }
+                    },
+                    "201": {
+                        "description": "Вариант добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/models.LyricsVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный язык или формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет перевод или транслитерацию текста песни на языке lang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удаление варианта текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Язык, тег BCP 47",
+                        "name": "lang",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Вариант удален",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный язык",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Вариант текста на этом языке не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/restore": {
+            "post": {
+                "description": "Возвращает удаленную песню из корзины и добавляет в ее историю ревизию restore",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Восстановление песни из корзины",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня восстановлена",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни не предоставлен или некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена в корзине",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "У исполнителя уже есть песня с таким названием, ее путь в Location",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions": {
+            "get": {
+                "description": "Возвращает ревизии песни от последней к первой: действие, автор (заголовок X-Actor), изменившиеся поля. История удаленной песни тоже доступна",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение истории правок песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.SongRevisionsWithPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни или параметры пагинации некорректны",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "У песни нет истории правок",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}": {
+            "get": {
+                "description": "Возвращает ревизию со снимком полей песни и отличия от предыдущей ревизии, для текста - построчно",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение ревизии песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер ревизии",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.SongRevisionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни или номер ревизии некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Ревизия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}/restore": {
+            "post": {
+                "description": "Возвращает песне название, исполнителя, текст, ссылку, дату выпуска и язык из ревизии. Восстановление записывается новой ревизией",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Восстановление песни из ревизии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер ревизии",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag песни из GET /songs/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня восстановлена",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Новая версия песни"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни или номер ревизии некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или ревизия не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "У исполнителя уже есть другая песня с названием из ревизии, ее путь в Location",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "412": {
+                        "description": "Песня изменена, версия не совпадает с If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "422": {
+                        "description": "Поля ревизии не проходят текущую валидацию песни (нарушения в details)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "428": {
+                        "description": "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/tags": {
+            "put": {
+                "description": "Заменяет теги песни переданным списком, несуществующие теги создаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Установка тегов песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Теги песни, пустой список снимает все теги",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SongTagsPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Теги песни обновлены",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/verses": {
+            "get": {
+                "description": "Возвращает куплеты песни по порядку",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Получение куплетов песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Verse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, ID песни не предоставлен или некорректен",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Вставляет куплет на позицию position, последующие куплеты сдвигаются. Текст песни пересобирается из куплетов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Добавление куплета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Куплет",
+                        "name": "verse",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VersePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Куплет добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/models.Verse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня с предоставленным ID не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/verses/{verse_id}": {
+            "put": {
+                "description": "Заменяет тип и текст куплета, позиция не меняется. Текст песни пересобирается из куплетов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Изменение куплета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID куплета",
+                        "name": "verse_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Куплет",
+                        "name": "verse",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VersePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Куплет изменен",
+                        "schema": {
+                            "$ref": "#/definitions/models.Verse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или куплет не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет куплет, последующие куплеты сдвигаются. Текст песни пересобирается из куплетов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Удаление куплета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID куплета",
+                        "name": "verse_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Куплет удален",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или куплет не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Переносит куплет на позицию position, остальные куплеты сдвигаются. Позиция за пределами песни приводится к первой или последней",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Перемещение куплета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID куплета",
+                        "name": "verse_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая позиция",
+                        "name": "verse",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VerseMovePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Куплет перемещен",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или куплет не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Возвращает теги в алфавитном порядке с количеством песен",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получение всех тегов с пагинацией",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Страница, по умолчанию 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный ответ",
+                        "schema": {
+                            "$ref": "#/definitions/services.TagsWithPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, некорректные параметры пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает новый тег. Теги также создаются автоматически при установке тегов песни",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Добавление тега",
+                "parameters": [
+                    {
+                        "description": "Данные тега",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TagPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Тег успешно добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Тег с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "delete": {
+                "description": "Удаляет тег по ID и снимает его со всех песен",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удаление тега",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Тег успешно удален",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос, неправильный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Тег с предоставленным ID не найден",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/exceptions.Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "exceptions.Error": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "details": {
+                    "description": "нарушения в отдельных полях запроса, заполняются только при ошибке валидации",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/exceptions.ErrorDetail"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "exceptions.ErrorDetail": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AddSongPayload": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "song": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "handlers.AlbumPayload": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "cover_link": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AlbumTrackPayload": {
+            "type": "object",
+            "properties": {
+                "disc_number": {
+                    "type": "integer"
+                },
+                "track_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ArtistPayload": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.GenrePayload": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.LyricsVariantPayload": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "$ref": "#/definitions/models.LyricsVariantKind"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PlaylistEntryMovePayload": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.PlaylistEntryPayload": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                },
+                "song_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.PlaylistPayload": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SongGenresPayload": {
+            "type": "object",
+            "properties": {
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.SongTagsPayload": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.TagPayload": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.VerseMovePayload": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.VersePayload": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "$ref": "#/definitions/models.VerseKind"
+                },
+                "position": {
+                    "description": "позиция для вставки, 0 - в конец; при обновлении куплета не используется",
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Album": {
+            "description": "Альбом исполнителя с треклистом, упорядоченным по номеру диска и трека.",
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "artist_id": {
+                    "type": "integer"
+                },
+                "cover_link": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Song"
+                    }
+                }
+            }
+        },
+        "models.Artist": {
+            "description": "Исполнитель песен. Имена сравниваются без учета регистра и лишних пробелов.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EnrichmentJob": {
+            "description": "Задача обогащения песни: статус, количество попыток, последняя ошибка и время следующего запуска.",
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "song_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.EnrichmentJobStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EnrichmentJobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "processing",
+                "done",
+                "dead"
+            ],
+            "x-enum-varnames": [
+                "EnrichmentJobQueued",
+                "EnrichmentJobProcessing",
+                "EnrichmentJobDone",
+                "EnrichmentJobDead"
+            ]
+        },
+        "models.EnrichmentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "EnrichmentPending",
+                "EnrichmentCompleted",
+                "EnrichmentFailed"
+            ]
+        },
+        "models.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Genre": {
+            "description": "Жанр песни. Жанры образуют иерархию, например rock \u003e alternative rock.",
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Genre"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LyricsLine": {
+            "description": "Строка текста с временем начала в миллисекундах. Пустой текст - пауза между куплетами.",
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "time_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LyricsMatch": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lang": {
+                    "description": "язык варианта текста, в котором найдены куплеты; пусто - оригинал",
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "verses": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.LyricsVariant": {
+            "description": "Вариант текста песни на другом языке. Язык - тег BCP 47, например en или ru-Latn.",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.LyricsVariantKind"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LyricsVariantKind": {
+            "type": "string",
+            "enum": [
+                "translation",
+                "transliteration"
+            ],
+            "x-enum-varnames": [
+                "LyricsVariantTranslation",
+                "LyricsVariantTransliteration"
+            ]
+        },
+        "models.Playlist": {
+            "description": "Плейлист с упорядоченным списком песен.",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "entries_amount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaylistEntry": {
+            "description": "Запись плейлиста. Одна и та же песня может встречаться в плейлисте несколько раз. Позиция считается среди видимых записей: записи с песнями из корзины скрыты и не занимают позиций.",
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "song": {
+                    "$ref": "#/definitions/models.Song"
+                }
+            }
+        },
+        "models.Song": {
+            "description": "Структура, содержащая данные о песне, такие как группа, название песни, текст, дата выпуска и ссылка.",
+            "type": "object",
+            "properties": {
+                "album_id": {
+                    "type": "integer"
+                },
+                "artist_id": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "description": "время удаления в корзину, заполняется только в списке корзины",
+                    "type": "string"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "enrichment_status": {
+                    "$ref": "#/definitions/models.EnrichmentStatus"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group": {
+                    "type": "string"
+                },
+                "highlight": {
+                    "description": "совпадения с поисковым запросом, заполняется только в результатах поиска",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SongHighlight"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lang": {
+                    "description": "язык оригинального текста, тег BCP 47",
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "lyrics_lang": {
+                    "description": "язык варианта текста, выбранного вместо оригинала по Accept-Language или lang",
+                    "type": "string"
+                },
+                "match": {
+                    "description": "совпадение с нечетким запросом, заполняется только в результатах нечеткого поиска",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SongMatch"
+                        }
+                    ]
+                },
+                "release_date": {
+                    "type": "string"
+                },
+                "release_date_inherited": {
+                    "description": "true, если у песни нет своей даты выпуска и ReleaseDate взята из альбома",
+                    "type": "boolean"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "версия песни, увеличивается при каждой правке; отдается также в заголовке ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongFacets": {
+            "type": "object",
+            "properties": {
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                }
+            }
+        },
+        "models.SongHighlight": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "text": {
+                    "description": "фрагменты текста песни вокруг совпадений, разделенные \" ... \"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SongMatch": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "$ref": "#/definitions/models.SongMatchField"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SongMatchField": {
+            "type": "string",
+            "enum": [
+                "song",
+                "group"
+            ],
+            "x-enum-varnames": [
+                "MatchFieldSong",
+                "MatchFieldGroup"
+            ]
+        },
+        "models.SongRevision": {
+            "description": "Ревизия песни: снимок редактируемых полей после правки, изменившиеся поля и автор правки.",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.SongRevisionAction"
+                },
+                "actor": {
+                    "description": "значение заголовка X-Actor запроса, сделавшего правку",
+                    "type": "string"
+                },
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "restored_from": {
+                    "description": "номер ревизии, из которой восстановлена песня; у восстановления из корзины не заполняется",
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.SongSnapshot"
+                },
+                "song_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongRevisionAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore"
+            ],
+            "x-enum-varnames": [
+                "RevisionCreate",
+                "RevisionUpdate",
+                "RevisionDelete",
+                "RevisionRestore"
+            ]
+        },
+        "models.SongSnapshot": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "description": "дата в формате 2006-01-02, null - дата не указана",
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SongSuggestion": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "link": {
+                "match": {
+                    "$ref": "#/definitions/models.SongMatch"
+                },
+                "song": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tag": {
+            "description": "Свободная метка песни, например \"summer\" или \"live\".",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "songs_amount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Verse": {
+            "description": "Куплет песни. Позиция - номер куплета в песне, по ней выбирается диапазон verses в пагинации по куплетам.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "0 у куплетов перевода, они не хранятся отдельно",
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/models.VerseKind"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.VerseKind": {
+            "type": "string",
+            "enum": [
+                "verse",
+                "chorus",
+                "bridge"
+            ],
+            "x-enum-varnames": [
+                "VerseKindVerse",
+                "VerseKindChorus",
+                "VerseKindBridge"
+            ]
+        },
+        "services.AlbumPatch": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "cover_link": {
                     "type": "string"
                 },
                 "release_date": {
                     "type": "string"
                 },
-                "song": {
+                "title": {
                     "type": "string"
+                }
+            }
+        },
+        "services.AlbumsWithPagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
                 },
-                "text": {
+                "pages_amount": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Album"
+                    }
+                }
+            }
+        },
+        "services.ArtistsWithPagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "pages_amount": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Artist"
+                    }
+                }
+            }
+        },
+        "services.LyricsMatchesWithPagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "pages_amount": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LyricsMatch"
+                    }
+                }
+            }
+        },
+        "services.PlaylistWithPagination": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current_page": {
+                    "type": "integer"
+                },
+                "entries_amount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pages_amount": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaylistEntry"
+                    }
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
+        "services.PlaylistsWithPagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "pages_amount": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Playlist"
+                    }
+                }
+            }
+        },
         "services.SongByIdWithVersePagination": {
             "type": "object",
             "properties": {
-                "current_verse": {
+                "current_page": {
+                    "description": "страница и количество страниц по PerPage куплетов, при выборе диапазоном не заполняются",
+                    "type": "integer"
+                },
+                "pages_amount": {
+                    "type": "integer"
+                },
+                "per_page": {
                     "type": "integer"
                 },
                 "song": {
-                    "$ref": "#/definitions/models.Song"
+                    "description": "песня без текста, текст выбранных куплетов - в Verses",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    ]
+                },
+                "verses": {
+                    "description": "выбранные куплеты по порядку, позиция куплета - его номер в песне",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Verse"
+                    }
                 },
                 "verses_amount": {
                     "type": "integer"
                 }
             }
         },
+        "services.SongDocument": {
+            "description": "Редактируемые поля песни. null или отсутствие поля очищает его, song и group обязательны. Ошибки валидации возвращаются в details с кодами required, too_long, invalid_url, invalid_date, future_date, invalid_language.",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "lang": {
+                    "description": "язык оригинального текста, тег BCP 47",
+                    "type": "string",
+                    "example": "ru"
+                },
+                "link": {
+                    "description": "абсолютная http(s) ссылка",
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "release_date": {
+                    "description": "собственная дата выпуска песни YYYY-MM-DD не позже сегодняшней, null - дата берется из альбома",
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "song": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "text": {
+                    "type": "string",
+                    "maxLength": 100000
+                }
+            }
+        },
+        "services.SongEnrichment": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/models.EnrichmentJob"
+                },
+                "song_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.EnrichmentStatus"
+                }
+            }
+        },
+        "services.SongFieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "from": {},
+                "to": {}
+            }
+        },
+        "services.SongLyrics": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "description": "строки синхронизированного текста, пустой список у несинхронизированной песни",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LyricsLine"
+                    }
+                },
+                "song_id": {
+                    "type": "integer"
+                },
+                "synced": {
+                    "description": "true, если у песни есть строки с метками времени",
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.SongRevisionDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SongFieldChange"
+                    }
+                },
+                "previous": {
+                    "description": "номер предыдущей ревизии, 0 у первой ревизии песни",
+                    "type": "integer"
+                },
+                "revision": {
+                    "$ref": "#/definitions/models.SongRevision"
+                },
+                "text_diff": {
+                    "description": "построчная разница текста песни, только если текст изменился",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/textdiff.Line"
+                    }
+                }
+            }
+        },
+        "services.SongRevisionsWithPagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "pages_amount": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SongRevision"
+                    }
+                }
+            }
+        },
         "services.SongsWithPagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "facets": {
+                    "description": "количество найденных песен по жанрам и тегам, заполняется по запросу",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SongFacets"
+                        }
+                    ]
+                },
+                "next_cursor": {
+                    "description": "курсоры соседних страниц, заполняются только при пагинации курсором",
+                    "type": "string"
+                },
+                "pages_amount": {
+                    "description": "0, если количество не считалось (count=none)",
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Song"
+                    }
+                },
+                "total_items": {
+                    "description": "количество найденных песен, при count=estimate - приблизительное",
+                    "type": "integer"
+                }
+            }
+        },
+        "services.TagsWithPagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "pages_amount": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                }
+            }
+        },
+        "services.TrashedSongsWithPagination": {
             "type": "object",
             "properties": {
                 "current_page": {
@@ -393,6 +4513,30 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "textdiff.Line": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "$ref": "#/definitions/textdiff.Op"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "textdiff.Op": {
+            "type": "string",
+            "enum": [
+                "equal",
+                "delete",
+                "insert"
+            ],
+            "x-enum-varnames": [
+                "OpEqual",
+                "OpDelete",
+                "OpInsert"
+            ]
         }
     }
 }`
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -14,16 +15,86 @@ type PostgresConfig struct {
 	Password string
 	User     string
 	DbName   string
+	// конфигурация полнотекстового поиска postgres (default_text_search_config), например russian или english
+	SearchLanguage string
+	// минимальное сходство по словам (pg_trgm.word_similarity_threshold) для нечеткого поиска, от 0 до 1
+	FuzzySearchThreshold float64
+}
+
+type SongDetailsApiConfig struct {
+	Url                     string
+	Timeout                 time.Duration
+	MaxRetries              int
+	RetryBaseDelay          time.Duration
+	RetryMaxDelay           time.Duration
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+}
+
+type EnrichmentConfig struct {
+	Workers        int
+	PollInterval   time.Duration
+	LockTimeout    time.Duration
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+type IdempotencyConfig struct {
+	KeyTTL        time.Duration
+	LockTimeout   time.Duration
+	PurgeInterval time.Duration
+}
+
+type ConcurrencyConfig struct {
+	// изменение и удаление песни без If-Match отклоняются с 428
+	RequireIfMatch bool
+}
+
+type PaginationConfig struct {
+	CursorSecret string
 }
 
 type AppConfig struct {
-	Port     int
-	AppEnv   string
-	Postgres *PostgresConfig
+	Port           int
+	AppEnv         string
+	Postgres       *PostgresConfig
+	SongDetailsApi *SongDetailsApiConfig
+	Enrichment     *EnrichmentConfig
+	Trash          *TrashConfig
+	Idempotency    *IdempotencyConfig
+	Concurrency    *ConcurrencyConfig
+	Pagination     *PaginationConfig
 }
 
 func ReadFromEnv() *AppConfig {
 	viper.SetConfigFile("./.env")
+	viper.SetDefault("SEARCH_LANGUAGE", "russian")
+	viper.SetDefault("SEARCH_FUZZY_THRESHOLD", 0.3)
+	viper.SetDefault("SONG_DETAILS_API_TIMEOUT", 5*time.Second)
+	viper.SetDefault("SONG_DETAILS_API_MAX_RETRIES", 3)
+	viper.SetDefault("SONG_DETAILS_API_RETRY_BASE_DELAY", 200*time.Millisecond)
+	viper.SetDefault("SONG_DETAILS_API_RETRY_MAX_DELAY", 5*time.Second)
+	viper.SetDefault("SONG_DETAILS_API_BREAKER_FAILURE_THRESHOLD", 5)
+	viper.SetDefault("SONG_DETAILS_API_BREAKER_OPEN_TIMEOUT", 30*time.Second)
+	viper.SetDefault("ENRICHMENT_WORKERS", 4)
+	viper.SetDefault("ENRICHMENT_POLL_INTERVAL", time.Second)
+	viper.SetDefault("ENRICHMENT_LOCK_TIMEOUT", 5*time.Minute)
+	viper.SetDefault("ENRICHMENT_MAX_ATTEMPTS", 5)
+	viper.SetDefault("ENRICHMENT_RETRY_BASE_DELAY", 10*time.Second)
+	viper.SetDefault("ENRICHMENT_RETRY_MAX_DELAY", 10*time.Minute)
+	viper.SetDefault("TRASH_RETENTION", 30*24*time.Hour)
+	viper.SetDefault("TRASH_PURGE_INTERVAL", time.Hour)
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+	viper.SetDefault("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute)
+	viper.SetDefault("IDEMPOTENCY_PURGE_INTERVAL", time.Hour)
+	viper.SetDefault("REQUIRE_IF_MATCH", false)
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("error while reading config %s", err)
 	}
@@ -34,15 +105,57 @@ func ReadFromEnv() *AppConfig {
 		host = viper.GetString("POSTGRES_HOST")
 	}
 
+	appEnv := viper.GetString("APP_ENV")
+
+	// без адреса api деталей песни работает только окружение разработки, там поднимается фейковый api
+	if viper.GetString("SONG_DETAILS_API_URL") == "" && appEnv != "development" {
+		log.Fatalf("SONG_DETAILS_API_URL is required when APP_ENV is %q", appEnv)
+	}
+
 	return &AppConfig{
 		Port:   viper.GetInt("PORT"),
-		AppEnv: viper.GetString("APP_ENV"),
+		AppEnv: appEnv,
 		Postgres: &PostgresConfig{
 			Port:     viper.GetInt("POSTGRES_PORT"),
 			Host:     host,
 			User:     viper.GetString("POSTGRES_USER"),
 			Password: viper.GetString("POSTGRES_PWD"),
 			DbName:   viper.GetString("POSTGRES_DB_NAME"),
+			// russian также стеммит латиницу английским стеммером, поэтому подходит для смешанной библиотеки
+			SearchLanguage:       viper.GetString("SEARCH_LANGUAGE"),
+			FuzzySearchThreshold: viper.GetFloat64("SEARCH_FUZZY_THRESHOLD"),
+		},
+		SongDetailsApi: &SongDetailsApiConfig{
+			Url:                     viper.GetString("SONG_DETAILS_API_URL"),
+			Timeout:                 viper.GetDuration("SONG_DETAILS_API_TIMEOUT"),
+			MaxRetries:              viper.GetInt("SONG_DETAILS_API_MAX_RETRIES"),
+			RetryBaseDelay:          viper.GetDuration("SONG_DETAILS_API_RETRY_BASE_DELAY"),
+			RetryMaxDelay:           viper.GetDuration("SONG_DETAILS_API_RETRY_MAX_DELAY"),
+			BreakerFailureThreshold: viper.GetInt("SONG_DETAILS_API_BREAKER_FAILURE_THRESHOLD"),
+			BreakerOpenTimeout:      viper.GetDuration("SONG_DETAILS_API_BREAKER_OPEN_TIMEOUT"),
+		},
+		Enrichment: &EnrichmentConfig{
+			Workers:        viper.GetInt("ENRICHMENT_WORKERS"),
+			PollInterval:   viper.GetDuration("ENRICHMENT_POLL_INTERVAL"),
+			LockTimeout:    viper.GetDuration("ENRICHMENT_LOCK_TIMEOUT"),
+			MaxAttempts:    viper.GetInt("ENRICHMENT_MAX_ATTEMPTS"),
+			RetryBaseDelay: viper.GetDuration("ENRICHMENT_RETRY_BASE_DELAY"),
+			RetryMaxDelay:  viper.GetDuration("ENRICHMENT_RETRY_MAX_DELAY"),
+		},
+		Trash: &TrashConfig{
+			Retention:     viper.GetDuration("TRASH_RETENTION"),
+			PurgeInterval: viper.GetDuration("TRASH_PURGE_INTERVAL"),
+		},
+		Idempotency: &IdempotencyConfig{
+			KeyTTL:        viper.GetDuration("IDEMPOTENCY_KEY_TTL"),
+			LockTimeout:   viper.GetDuration("IDEMPOTENCY_LOCK_TIMEOUT"),
+			PurgeInterval: viper.GetDuration("IDEMPOTENCY_PURGE_INTERVAL"),
+		},
+		Concurrency: &ConcurrencyConfig{
+			RequireIfMatch: viper.GetBool("REQUIRE_IF_MATCH"),
+		},
+		Pagination: &PaginationConfig{
+			CursorSecret: viper.GetString("PAGINATION_CURSOR_SECRET"),
 		},
 	}
 }
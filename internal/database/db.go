@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -24,7 +25,16 @@ func New(config *config.PostgresConfig, ctx context.Context) (*pgxpool.Pool, err
 		config.DbName,
 	)
 
-	db, err := pgxpool.New(ctx, connStr)
+	poolConfig, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing database config: %w", err)
+	}
+
+	// запросы и новые песни используют конфигурацию поиска соединения, неизвестная конфигурация не даст подключиться
+	poolConfig.ConnConfig.RuntimeParams["default_text_search_config"] = config.SearchLanguage
+	poolConfig.ConnConfig.RuntimeParams["pg_trgm.word_similarity_threshold"] = strconv.FormatFloat(config.FuzzySearchThreshold, 'f', -1, 64)
+
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("error while creating a connection to database: %w", err)
 	}
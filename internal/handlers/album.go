@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/internal/services"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+type AlbumPayload struct {
+	Title       string     `json:"title"`
+	Artist      string     `json:"artist"`
+	ReleaseDate *time.Time `json:"release_date"`
+	CoverLink   string     `json:"cover_link"`
+}
+
+type AlbumTrackPayload struct {
+	DiscNumber  int `json:"disc_number"`
+	TrackNumber int `json:"track_number"`
+}
+
+// GetAlbumsWithPagination godoc
+// @Summary Получение всех альбомов с пагинацией
+// @Description Возвращает список альбомов без треклистов, новые релизы первыми
+// @Tags albums
+// @Produce  json
+// @Param   page              query     int        false   "Страница, по умолчанию 1" minimum(1)
+// @Param   limit             query     int        false   "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.AlbumsWithPagination    "Успешный ответ"
+// @Failure 400 {object} exceptions.Error                 "Неверный запрос, некорректные параметры пагинации"
+// @Failure 500 {object} exceptions.Error                 "Внутренняя ошибка сервера"
+// @Router  /albums [get]
+func (cntrl *Controller) GetAlbumsWithPagination(c *gin.Context) {
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	albums, err := cntrl.albumService.GetAllAlbumsWithPagination(c.Request.Context(), limit, page)
+	if err != nil {
+		logrus.Debugf("get all albums with pagination error: %s", err)
+		exceptions.FetchingAlbumsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, albums)
+}
+
+// GetAlbumById godoc
+// @Summary Получение альбома по ID
+// @Description Возвращает альбом с треками, упорядоченными по номеру диска и трека
+// @Tags albums
+// @Produce json
+// @Param   id      path     int     true    "ID альбома"
+// @Success 200 {object} models.Album     "Альбом успешно найден"
+// @Failure 400 {object} exceptions.Error "Неверный запрос, ID альбома не предоставлен или некорректен"
+// @Failure 404 {object} exceptions.Error "Альбом с предоставленным ID не найден"
+// @Router  /albums/{id} [get]
+func (cntrl *Controller) GetAlbumById(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.AlbumIdIsNotProvidedError, exceptions.FailedToParseAlbumIdError)
+	if !ok {
+		return
+	}
+
+	album, err := cntrl.albumService.GetAlbumById(c.Request.Context(), id)
+	if err != nil {
+		logrus.Debugf("get album by id error: %s", err)
+		exceptions.AlbumByIdNotFoundError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// AddAlbum godoc
+// @Summary Добавление альбома
+// @Description Создает новый альбом, исполнитель находится по имени или создается
+// @Tags albums
+// @Accept  json
+// @Produce  json
+// @Param album body AlbumPayload true "Данные альбома"
+// @Success 201 {object} models.Album     "Альбом успешно добавлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /albums [post]
+func (cntrl *Controller) AddAlbum(c *gin.Context) {
+	var payload AlbumPayload
+	err := c.BindJSON(&payload)
+	if err != nil || strings.TrimSpace(payload.Title) == "" || strings.TrimSpace(payload.Artist) == "" {
+		exceptions.InvalidPayloadToSaveAnAlbumError(c)
+		return
+	}
+
+	album := models.Album{
+		Title:       payload.Title,
+		Artist:      payload.Artist,
+		ReleaseDate: payload.ReleaseDate,
+		CoverLink:   payload.CoverLink,
+	}
+
+	err = cntrl.albumService.AddAlbum(c.Request.Context(), &album)
+	if err != nil {
+		logrus.Debugf("add album error: %s", err)
+		exceptions.CreatingAlbumError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, album)
+}
+
+// UpdateAlbum godoc
+// @Summary Обновление альбома
+// @Description Обновляет переданные поля альбома
+// @Tags albums
+// @Accept  json
+// @Produce  json
+// @Param  id     path  int                  true  "ID альбома"
+// @Param  album  body  services.AlbumPatch  true  "Изменяемые поля альбома"
+// @Success 200 {object} models.Album     "Альбом успешно обновлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Альбом с предоставленным ID не найден"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /albums/{id} [patch]
+func (cntrl *Controller) UpdateAlbum(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.AlbumIdIsNotProvidedError, exceptions.FailedToParseAlbumIdError)
+	if !ok {
+		return
+	}
+
+	var patch services.AlbumPatch
+	err := c.BindJSON(&patch)
+	if err != nil ||
+		(patch.Title != nil && strings.TrimSpace(*patch.Title) == "") ||
+		(patch.Artist != nil && strings.TrimSpace(*patch.Artist) == "") {
+		exceptions.InvalidPayloadToSaveAnAlbumError(c)
+		return
+	}
+
+	album, err := cntrl.albumService.UpdateAlbum(c.Request.Context(), id, patch)
+	if errors.Is(err, models.ErrAlbumNotFound) {
+		exceptions.AlbumByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("update album error: %s", err)
+		exceptions.UpdatingAlbumError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// DeleteAlbum godoc
+// @Summary Удаление альбома
+// @Description Удаляет альбом по ID, песни альбома остаются в библиотеке
+// @Tags albums
+// @Produce  json
+// @Param  id  path  int  true  "ID альбома"
+// @Success 204 {object} any              "Альбом успешно удален"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Альбом с предоставленным ID не найден"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /albums/{id} [delete]
+func (cntrl *Controller) DeleteAlbum(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.AlbumIdIsNotProvidedError, exceptions.FailedToParseAlbumIdError)
+	if !ok {
+		return
+	}
+
+	err := cntrl.albumService.DeleteAlbum(c.Request.Context(), id)
+	if errors.Is(err, models.ErrAlbumNotFound) {
+		exceptions.AlbumByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("delete album error: %s", err)
+		exceptions.DeletingAlbumError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetAlbumTrack godoc
+// @Summary Добавление песни в треклист альбома
+// @Description Ставит песню на указанную позицию (диск и номер трека) альбома, песня может быть перенесена из другого альбома
+// @Tags albums
+// @Accept  json
+// @Produce  json
+// @Param  id       path  int                true  "ID альбома"
+// @Param  song_id  path  int                true  "ID песни"
+// @Param  track    body  AlbumTrackPayload  true  "Позиция трека, disc_number по умолчанию 1"
+// @Success 200 {object} models.Album     "Треклист успешно обновлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Альбом или песня не найдены"
+// @Failure 409 {object} exceptions.Error "Позиция трека уже занята"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /albums/{id}/tracks/{song_id} [put]
+func (cntrl *Controller) SetAlbumTrack(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.AlbumIdIsNotProvidedError, exceptions.FailedToParseAlbumIdError)
+	if !ok {
+		return
+	}
+
+	songId, ok := parseIdParam(c, "song_id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	var payload AlbumTrackPayload
+	err := c.BindJSON(&payload)
+	if err != nil || payload.TrackNumber < 1 || payload.DiscNumber < 0 {
+		exceptions.InvalidPayloadToSaveAnAlbumError(c)
+		return
+	}
+
+	album, err := cntrl.albumService.SetAlbumTrack(c.Request.Context(), id, songId, payload.DiscNumber, payload.TrackNumber)
+	switch {
+	case errors.Is(err, models.ErrAlbumNotFound):
+		exceptions.AlbumByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrAlbumTrackNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrAlbumTrackTaken):
+		exceptions.AlbumTrackTakenError(c)
+		return
+	case err != nil:
+		logrus.Debugf("set album track error: %s", err)
+		exceptions.UpdatingAlbumError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// RemoveAlbumTrack godoc
+// @Summary Удаление песни из треклиста альбома
+// @Description Убирает песню из альбома, сама песня остается в библиотеке
+// @Tags albums
+// @Produce  json
+// @Param  id       path  int  true  "ID альбома"
+// @Param  song_id  path  int  true  "ID песни"
+// @Success 204 {object} any              "Песня убрана из альбома"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Песня не найдена в альбоме"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /albums/{id}/tracks/{song_id} [delete]
+func (cntrl *Controller) RemoveAlbumTrack(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.AlbumIdIsNotProvidedError, exceptions.FailedToParseAlbumIdError)
+	if !ok {
+		return
+	}
+
+	songId, ok := parseIdParam(c, "song_id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	err := cntrl.albumService.RemoveAlbumTrack(c.Request.Context(), id, songId)
+	if errors.Is(err, models.ErrAlbumTrackNotFound) {
+		exceptions.AlbumTrackNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("remove album track error: %s", err)
+		exceptions.UpdatingAlbumError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+type ArtistPayload struct {
+	Name string `json:"name"`
+}
+
+// GetArtistsWithPagination godoc
+// @Summary Получение всех исполнителей с пагинацией
+// @Description Возвращает список исполнителей, отсортированный по имени
+// @Tags artists
+// @Produce  json
+// @Param   page              query     int        false   "Страница, по умолчанию 1" minimum(1)
+// @Param   limit             query     int        false   "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.ArtistsWithPagination   "Успешный ответ"
+// @Failure 400 {object} exceptions.Error                 "Неверный запрос, некорректные параметры пагинации"
+// @Failure 500 {object} exceptions.Error                 "Внутренняя ошибка сервера"
+// @Router  /artists [get]
+func (cntrl *Controller) GetArtistsWithPagination(c *gin.Context) {
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	artists, err := cntrl.artistService.GetAllArtistsWithPagination(c.Request.Context(), limit, page)
+	if err != nil {
+		logrus.Debugf("get all artists with pagination error: %s", err)
+		exceptions.FetchingArtistsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, artists)
+}
+
+// GetArtistById godoc
+// @Summary Получение исполнителя по ID
+// @Description Возвращает информацию об исполнителе по указанному ID
+// @Tags artists
+// @Produce json
+// @Param   id      path     int     true    "ID исполнителя"
+// @Success 200 {object} models.Artist    "Исполнитель успешно найден"
+// @Failure 400 {object} exceptions.Error "Неверный запрос, ID исполнителя не предоставлен или некорректен"
+// @Failure 404 {object} exceptions.Error "Исполнитель с предоставленным ID не найден"
+// @Router  /artists/{id} [get]
+func (cntrl *Controller) GetArtistById(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.ArtistIdIsNotProvidedError, exceptions.FailedToParseArtistIdError)
+	if !ok {
+		return
+	}
+
+	artist, err := cntrl.artistService.GetArtistById(c.Request.Context(), id)
+	if err != nil {
+		logrus.Debugf("get artist by id error: %s", err)
+		exceptions.ArtistByIdNotFoundError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// GetArtistSongsWithPagination godoc
+// @Summary Дискография исполнителя
+// @Description Возвращает песни исполнителя с пагинацией, отсортированные по дате выпуска
+// @Tags artists
+// @Produce json
+// @Param   id      path     int     true    "ID исполнителя"
+// @Param   page    query    int     false   "Страница, по умолчанию 1" minimum(1)
+// @Param   limit   query    int     false   "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.SongsWithPagination "Успешный ответ"
+// @Failure 400 {object} exceptions.Error             "Неверный запрос, ID исполнителя или параметры пагинации некорректны"
+// @Failure 404 {object} exceptions.Error             "Исполнитель с предоставленным ID не найден"
+// @Failure 500 {object} exceptions.Error             "Внутренняя ошибка сервера"
+// @Router  /artists/{id}/songs [get]
+func (cntrl *Controller) GetArtistSongsWithPagination(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.ArtistIdIsNotProvidedError, exceptions.FailedToParseArtistIdError)
+	if !ok {
+		return
+	}
+
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	songs, err := cntrl.artistService.GetArtistSongsWithPagination(c.Request.Context(), id, limit, page)
+	if errors.Is(err, models.ErrArtistNotFound) {
+		exceptions.ArtistByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("get artist songs with pagination error: %s", err)
+		exceptions.FetchingSongsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, songs)
+}
+
+// AddArtist godoc
+// @Summary Добавление исполнителя
+// @Description Создает нового исполнителя
+// @Tags artists
+// @Accept  json
+// @Produce  json
+// @Param artist body ArtistPayload true "Данные исполнителя"
+// @Success 201 {object} models.Artist    "Исполнитель успешно добавлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 409 {object} exceptions.Error "Исполнитель с таким именем уже существует"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /artists [post]
+func (cntrl *Controller) AddArtist(c *gin.Context) {
+	var payload ArtistPayload
+	err := c.BindJSON(&payload)
+	if err != nil || strings.TrimSpace(payload.Name) == "" {
+		exceptions.InvalidPayloadToSaveAnArtistError(c)
+		return
+	}
+
+	artist := models.Artist{Name: payload.Name}
+	err = cntrl.artistService.AddArtist(c.Request.Context(), &artist)
+	if errors.Is(err, models.ErrArtistAlreadyExists) {
+		exceptions.ArtistAlreadyExistsError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("add artist error: %s", err)
+		exceptions.CreatingArtistError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, artist)
+}
+
+// UpdateArtist godoc
+// @Summary Переименование исполнителя
+// @Description Обновляет имя исполнителя, имя исполнителя в его песнях обновляется вместе с ним
+// @Tags artists
+// @Accept  json
+// @Produce  json
+// @Param  id      path  int            true  "ID исполнителя"
+// @Param  artist  body  ArtistPayload  true  "Данные исполнителя"
+// @Success 200 {object} models.Artist    "Исполнитель успешно обновлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Исполнитель с предоставленным ID не найден"
+// @Failure 409 {object} exceptions.Error "Исполнитель с таким именем уже существует"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /artists/{id} [patch]
+func (cntrl *Controller) UpdateArtist(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.ArtistIdIsNotProvidedError, exceptions.FailedToParseArtistIdError)
+	if !ok {
+		return
+	}
+
+	var payload ArtistPayload
+	err := c.BindJSON(&payload)
+	if err != nil || strings.TrimSpace(payload.Name) == "" {
+		exceptions.InvalidPayloadToSaveAnArtistError(c)
+		return
+	}
+
+	artist, err := cntrl.artistService.UpdateArtist(c.Request.Context(), id, models.Artist{Name: payload.Name})
+	switch {
+	case errors.Is(err, models.ErrArtistNotFound):
+		exceptions.ArtistByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrArtistAlreadyExists):
+		exceptions.ArtistAlreadyExistsError(c)
+		return
+	case err != nil:
+		logrus.Debugf("update artist error: %s", err)
+		exceptions.UpdatingArtistError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// DeleteArtist godoc
+// @Summary Удаление исполнителя
+// @Description Удаляет исполнителя по ID, если у него нет песен
+// @Tags artists
+// @Produce  json
+// @Param  id  path  int  true  "ID исполнителя"
+// @Success 204 {object} any              "Исполнитель успешно удален"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Исполнитель с предоставленным ID не найден"
+// @Failure 409 {object} exceptions.Error "У исполнителя есть песни"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /artists/{id} [delete]
+func (cntrl *Controller) DeleteArtist(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.ArtistIdIsNotProvidedError, exceptions.FailedToParseArtistIdError)
+	if !ok {
+		return
+	}
+
+	err := cntrl.artistService.DeleteArtist(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, models.ErrArtistNotFound):
+		exceptions.ArtistByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrArtistHasSongs):
+		exceptions.ArtistHasSongsError(c)
+		return
+	case err != nil:
+		logrus.Debugf("delete artist error: %s", err)
+		exceptions.DeletingArtistError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
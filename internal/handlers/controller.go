@@ -5,16 +5,43 @@ import (
 )
 
 type Controller struct {
-	songService           *services.SongService
-	songDetailsApiService services.SongDetailsApiService
+	songService       *services.SongService
+	enrichmentService *services.EnrichmentService
+	artistService     *services.ArtistService
+	albumService      *services.AlbumService
+	playlistService   *services.PlaylistService
+	genreService      *services.GenreService
+	tagService        *services.TagService
+	verseService      *services.VerseService
+	lyricsService     *services.LyricsService
+	revisionService   *services.RevisionService
+	trashService      *services.TrashService
 }
 
 func NewController(
 	songService *services.SongService,
-	songDetailsApiService services.SongDetailsApiService,
+	enrichmentService *services.EnrichmentService,
+	artistService *services.ArtistService,
+	albumService *services.AlbumService,
+	playlistService *services.PlaylistService,
+	genreService *services.GenreService,
+	tagService *services.TagService,
+	verseService *services.VerseService,
+	lyricsService *services.LyricsService,
+	revisionService *services.RevisionService,
+	trashService *services.TrashService,
 ) *Controller {
 	return &Controller{
 		songService,
-		songDetailsApiService,
+		enrichmentService,
+		artistService,
+		albumService,
+		playlistService,
+		genreService,
+		tagService,
+		verseService,
+		lyricsService,
+		revisionService,
+		trashService,
 	}
 }
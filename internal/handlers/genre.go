@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+type GenrePayload struct {
+	Name     string `json:"name"`
+	ParentId *int64 `json:"parent_id"`
+}
+
+type SongGenresPayload struct {
+	Genres []string `json:"genres"`
+}
+
+// GetGenres godoc
+// @Summary Получение дерева жанров
+// @Description Возвращает корневые жанры, поджанры вложены в children
+// @Tags genres
+// @Produce  json
+// @Success 200 {object} []models.Genre   "Успешный ответ"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router  /genres [get]
+func (cntrl *Controller) GetGenres(c *gin.Context) {
+	genres, err := cntrl.genreService.GetGenreTree(c.Request.Context())
+	if err != nil {
+		logrus.Debugf("get genre tree error: %s", err)
+		exceptions.FetchingGenresError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// GetGenreById godoc
+// @Summary Получение жанра по ID
+// @Description Возвращает жанр без поджанров
+// @Tags genres
+// @Produce json
+// @Param   id      path     int     true    "ID жанра"
+// @Success 200 {object} models.Genre     "Жанр успешно найден"
+// @Failure 400 {object} exceptions.Error "Неверный запрос, ID жанра не предоставлен или некорректен"
+// @Failure 404 {object} exceptions.Error "Жанр с предоставленным ID не найден"
+// @Router  /genres/{id} [get]
+func (cntrl *Controller) GetGenreById(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.GenreIdIsNotProvidedError, exceptions.FailedToParseGenreIdError)
+	if !ok {
+		return
+	}
+
+	genre, err := cntrl.genreService.GetGenreById(c.Request.Context(), id)
+	if err != nil {
+		logrus.Debugf("get genre by id error: %s", err)
+		exceptions.GenreByIdNotFoundError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// AddGenre godoc
+// @Summary Добавление жанра
+// @Description Создает новый жанр, parent_id делает его поджанром существующего жанра
+// @Tags genres
+// @Accept  json
+// @Produce  json
+// @Param genre body GenrePayload true "Данные жанра"
+// @Success 201 {object} models.Genre     "Жанр успешно добавлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных или родительский жанр не найден"
+// @Failure 409 {object} exceptions.Error "Жанр с таким названием уже существует"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /genres [post]
+func (cntrl *Controller) AddGenre(c *gin.Context) {
+	var payload GenrePayload
+	err := c.BindJSON(&payload)
+	if err != nil || strings.TrimSpace(payload.Name) == "" {
+		exceptions.InvalidPayloadToSaveAGenreError(c)
+		return
+	}
+
+	genre := models.Genre{
+		Name:     payload.Name,
+		ParentId: payload.ParentId,
+	}
+
+	err = cntrl.genreService.AddGenre(c.Request.Context(), &genre)
+	switch {
+	case errors.Is(err, models.ErrGenreAlreadyExists):
+		exceptions.GenreAlreadyExistsError(c)
+		return
+	case errors.Is(err, models.ErrGenreParentNotFound):
+		exceptions.GenreParentNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("add genre error: %s", err)
+		exceptions.CreatingGenreError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, genre)
+}
+
+// UpdateGenre godoc
+// @Summary Обновление жанра
+// @Description Переименовывает жанр и/или переносит его под другой жанр, без parent_id жанр становится корневым
+// @Tags genres
+// @Accept  json
+// @Produce  json
+// @Param  id     path  int           true  "ID жанра"
+// @Param  genre  body  GenrePayload  true  "Данные жанра"
+// @Success 200 {object} models.Genre     "Жанр успешно обновлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, родительский жанр не найден или является поджанром"
+// @Failure 404 {object} exceptions.Error "Жанр с предоставленным ID не найден"
+// @Failure 409 {object} exceptions.Error "Жанр с таким названием уже существует"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /genres/{id} [put]
+func (cntrl *Controller) UpdateGenre(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.GenreIdIsNotProvidedError, exceptions.FailedToParseGenreIdError)
+	if !ok {
+		return
+	}
+
+	var payload GenrePayload
+	err := c.BindJSON(&payload)
+	if err != nil || strings.TrimSpace(payload.Name) == "" {
+		exceptions.InvalidPayloadToSaveAGenreError(c)
+		return
+	}
+
+	genre, err := cntrl.genreService.UpdateGenre(c.Request.Context(), id, &models.Genre{
+		Name:     payload.Name,
+		ParentId: payload.ParentId,
+	})
+	switch {
+	case errors.Is(err, models.ErrGenreNotFound):
+		exceptions.GenreByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrGenreAlreadyExists):
+		exceptions.GenreAlreadyExistsError(c)
+		return
+	case errors.Is(err, models.ErrGenreParentNotFound):
+		exceptions.GenreParentNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrGenreCycle):
+		exceptions.GenreCycleError(c)
+		return
+	case err != nil:
+		logrus.Debugf("update genre error: %s", err)
+		exceptions.UpdatingGenreError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// DeleteGenre godoc
+// @Summary Удаление жанра
+// @Description Удаляет жанр по ID и снимает его со всех песен. Жанр с поджанрами удалить нельзя
+// @Tags genres
+// @Produce  json
+// @Param  id  path  int  true  "ID жанра"
+// @Success 204 {object} any              "Жанр успешно удален"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Жанр с предоставленным ID не найден"
+// @Failure 409 {object} exceptions.Error "У жанра есть поджанры"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /genres/{id} [delete]
+func (cntrl *Controller) DeleteGenre(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.GenreIdIsNotProvidedError, exceptions.FailedToParseGenreIdError)
+	if !ok {
+		return
+	}
+
+	err := cntrl.genreService.DeleteGenre(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, models.ErrGenreNotFound):
+		exceptions.GenreByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrGenreHasSubgenres):
+		exceptions.GenreHasSubgenresError(c)
+		return
+	case err != nil:
+		logrus.Debugf("delete genre error: %s", err)
+		exceptions.DeletingGenreError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetSongGenres godoc
+// @Summary Установка жанров песни
+// @Description Заменяет жанры песни переданным списком, жанры указываются по названию и должны существовать
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param  id      path  int                true  "ID песни"
+// @Param  genres  body  SongGenresPayload  true  "Названия жанров, пустой список снимает все жанры"
+// @Success 200 {object} models.Song      "Жанры песни обновлены"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос или жанр не найден"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/genres [put]
+func (cntrl *Controller) SetSongGenres(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	var payload SongGenresPayload
+	err := c.BindJSON(&payload)
+	if err != nil || len(nonEmpty(payload.Genres)) != len(payload.Genres) {
+		exceptions.InvalidPayloadToSaveSongClassificationError(c)
+		return
+	}
+
+	err = cntrl.genreService.SetSongGenres(c.Request.Context(), id, payload.Genres)
+	switch {
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrGenreNotFound):
+		exceptions.UnknownSongGenreError(c)
+		return
+	case err != nil:
+		logrus.Debugf("set song genres error: %s", err)
+		exceptions.UpdatingSongError(c)
+		return
+	}
+
+	song, err := cntrl.songService.GetSongById(c.Request.Context(), id)
+	if err != nil {
+		logrus.Debugf("get song by id error: %s", err)
+		exceptions.SongByIdNotFoundError(c)
+		return
+	}
+
+	c.Header("ETag", songETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
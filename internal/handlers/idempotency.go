@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/internal/services"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	// ограничение размера тела запроса с ключом идемпотентности, тело хешируется целиком
+	maxIdempotentBodySize = 1 << 20
+)
+
+// заголовки ответа, которые сохраняются и возвращаются при повторе
+var idempotentHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// recordingWriter копирует тело ответа, чтобы сохранить его для повторов запроса
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware выполняет запрос с заголовком Idempotency-Key один раз: повтор с тем же ключом и телом
+// получает сохраненный ответ с заголовком Idempotent-Replayed. Ответы 5xx не сохраняются, такой запрос можно повторить
+func IdempotencyMiddleware(is *services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if !isValidIdempotencyKey(key) {
+			exceptions.InvalidIdempotencyKeyError(c)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			exceptions.RequestBodyTooLargeError(c)
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+		saved, err := is.Begin(c.Request.Context(), key, fingerprint)
+		switch {
+		case errors.Is(err, models.ErrIdempotencyKeyReused):
+			exceptions.IdempotencyKeyReusedError(c)
+			return
+		case errors.Is(err, models.ErrIdempotencyKeyInProgress):
+			exceptions.IdempotencyKeyInProgressError(c)
+			return
+		case err != nil:
+			logrus.Debugf("begin idempotent request error: %s", err)
+			exceptions.IdempotencyError(c)
+			return
+		case saved != nil:
+			for name, value := range saved.Headers {
+				c.Header(name, value)
+			}
+
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(saved.Status, saved.Headers["Content-Type"], saved.Body)
+			c.Abort()
+			return
+		}
+
+		// ключ сохраняется и после отмены запроса клиентом, иначе повтор выполнит запрос еще раз
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		var response *models.IdempotentResponse
+		defer func() {
+			// ответ 5xx или паника: ответ не сохраняется, ключ освобождается для повтора
+			if response == nil {
+				if err := is.Release(ctx, key); err != nil {
+					logrus.Errorf("release idempotency key error: %s", err)
+				}
+			}
+		}()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		response = &models.IdempotentResponse{
+			Status:  writer.Status(),
+			Headers: make(map[string]string, len(idempotentHeaders)),
+			Body:    writer.body.Bytes(),
+		}
+
+		for _, name := range idempotentHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				response.Headers[name] = value
+			}
+		}
+
+		if err := is.Complete(ctx, key, response); err != nil {
+			logrus.Errorf("save idempotent response error: %s", err)
+		}
+	}
+}
+
+// isValidIdempotencyKey проверяет, что ключ состоит из 1-255 печатных символов ASCII
+func isValidIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/internal/services"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	lyricsFormatLrc   = "lrc"
+	lyricsFormatJson  = "json"
+	lyricsFormatPlain = "plain"
+
+	// ограничение размера загружаемого LRC, текст песни заметно меньше
+	maxLrcSize = 1 << 20
+)
+
+// GetSongLyrics godoc
+// @Summary Получение текста песни
+// @Description Возвращает текст песни в формате format: json - текст и строки с метками времени, lrc - файл LRC
+// @Description (только для синхронизированного текста), plain - текст без меток времени.
+// @Tags songs
+// @Produce  json
+// @Produce  plain
+// @Param  id      path   int     true   "ID песни"
+// @Param  format  query  string  false  "Формат: json (по умолчанию), lrc или plain"
+// @Success 200 {object} services.SongLyrics "Успешный ответ"
+// @Failure 400 {object} exceptions.Error    "Неверный запрос, ID песни или формат некорректен"
+// @Failure 404 {object} exceptions.Error    "Песня не найдена или текст песни не синхронизирован"
+// @Failure 500 {object} exceptions.Error    "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics [get]
+func (cntrl *Controller) GetSongLyrics(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", lyricsFormatJson)
+	switch format {
+	case lyricsFormatLrc:
+		data, err := cntrl.lyricsService.ExportLrc(c.Request.Context(), id)
+		if !handleLyricsError(c, err) {
+			return
+		}
+
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(data))
+	case lyricsFormatJson, lyricsFormatPlain:
+		lyrics, err := cntrl.lyricsService.GetLyrics(c.Request.Context(), id)
+		if !handleLyricsError(c, err) {
+			return
+		}
+
+		if format == lyricsFormatPlain {
+			c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(lyrics.Text))
+			return
+		}
+
+		c.JSON(http.StatusOK, lyrics)
+	default:
+		exceptions.InvalidLyricsFormatError(c)
+	}
+}
+
+// UploadSongLyrics godoc
+// @Summary Загрузка синхронизированного текста песни
+// @Description Принимает файл LRC полем file формы multipart/form-data или телом запроса. Метки времени должны строго возрастать.
+// @Description Текст и куплеты песни собираются из строк файла, строки без текста разделяют куплеты.
+// @Description Изменение текста песни или ее куплетов другими запросами удаляет синхронизацию.
+// @Tags songs
+// @Accept  plain
+// @Accept  mpfd
+// @Produce  json
+// @Param  id    path      int     true   "ID песни"
+// @Param  file  formData  file    false  "Файл LRC"
+// @Success 200 {object} services.SongLyrics "Текст сохранен"
+// @Failure 400 {object} exceptions.Error    "Некорректный запрос, файл LRC не разобран"
+// @Failure 404 {object} exceptions.Error    "Песня с предоставленным ID не найдена"
+// @Failure 500 {object} exceptions.Error    "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics [put]
+func (cntrl *Controller) UploadSongLyrics(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	data, err := readLrc(c)
+	if err != nil {
+		logrus.Debugf("read lrc error: %s", err)
+		exceptions.InvalidLrcFileError(c)
+		return
+	}
+
+	lyrics, err := cntrl.lyricsService.UploadLrc(c.Request.Context(), id, data)
+	switch {
+	case errors.Is(err, services.ErrInvalidLrc):
+		logrus.Debugf("parse lrc error: %s", err)
+		exceptions.InvalidLrcFileError(c)
+		return
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("upload lrc error: %s", err)
+		exceptions.SavingLyricsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, lyrics)
+}
+
+type LyricsVariantPayload struct {
+	Kind models.LyricsVariantKind `json:"kind"`
+	Text string                   `json:"text"`
+}
+
+// GetLyricsVariants godoc
+// @Summary Получение вариантов текста песни
+// @Description Возвращает переводы и транслитерации текста песни, упорядоченные по языку
+// @Tags songs
+// @Produce  json
+// @Param  id  path  int  true  "ID песни"
+// @Success 200 {object} []models.LyricsVariant "Успешный ответ"
+// @Failure 400 {object} exceptions.Error       "Неверный запрос, ID песни не предоставлен или некорректен"
+// @Failure 404 {object} exceptions.Error       "Песня с предоставленным ID не найдена"
+// @Failure 500 {object} exceptions.Error       "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics/variants [get]
+func (cntrl *Controller) GetLyricsVariants(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	variants, err := cntrl.lyricsService.GetVariants(c.Request.Context(), id)
+	if !handleLyricsError(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, variants)
+}
+
+// SaveLyricsVariant godoc
+// @Summary Добавление или изменение варианта текста песни
+// @Description Сохраняет перевод или транслитерацию текста песни на языке lang, вариант на том же языке заменяется.
+// @Description Текст делится на куплеты пустыми строками, как и текст песни.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param  id       path  int                   true  "ID песни"
+// @Param  lang     path  string                true  "Язык, тег BCP 47: en, ru-Latn"
+// @Param  variant  body  LyricsVariantPayload  true  "Вариант текста, kind по умолчанию translation"
+// @Success 200 {object} models.LyricsVariant "Вариант изменен"
+// @Success 201 {object} models.LyricsVariant "Вариант добавлен"
+// @Failure 400 {object} exceptions.Error     "Некорректный запрос, неправильный язык или формат данных"
+// @Failure 404 {object} exceptions.Error     "Песня с предоставленным ID не найдена"
+// @Failure 500 {object} exceptions.Error     "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics/variants/{lang} [put]
+func (cntrl *Controller) SaveLyricsVariant(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	lang, ok := parseLang(c.Param("lang"))
+	if !ok || lang == "" {
+		exceptions.InvalidLanguageError(c)
+		return
+	}
+
+	var payload LyricsVariantPayload
+	err := c.BindJSON(&payload)
+	if err != nil {
+		exceptions.InvalidPayloadToSaveLyricsVariantError(c)
+		return
+	}
+
+	if payload.Kind == "" {
+		payload.Kind = models.LyricsVariantTranslation
+	}
+
+	if !slices.Contains(models.LyricsVariantKinds, payload.Kind) {
+		exceptions.InvalidPayloadToSaveLyricsVariantError(c)
+		return
+	}
+
+	variant := &models.LyricsVariant{Lang: lang, Kind: payload.Kind, Text: payload.Text}
+	created, err := cntrl.lyricsService.SaveVariant(c.Request.Context(), id, variant)
+	switch {
+	case errors.Is(err, services.ErrInvalidLyricsVariant):
+		exceptions.InvalidPayloadToSaveLyricsVariantError(c)
+		return
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("save lyrics variant error: %s", err)
+		exceptions.SavingLyricsError(c)
+		return
+	}
+
+	if created {
+		c.JSON(http.StatusCreated, variant)
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+// DeleteLyricsVariant godoc
+// @Summary Удаление варианта текста песни
+// @Description Удаляет перевод или транслитерацию текста песни на языке lang
+// @Tags songs
+// @Produce  json
+// @Param  id    path  int     true  "ID песни"
+// @Param  lang  path  string  true  "Язык, тег BCP 47"
+// @Success 204 {object} any              "Вариант удален"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный язык"
+// @Failure 404 {object} exceptions.Error "Вариант текста на этом языке не найден"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/lyrics/variants/{lang} [delete]
+func (cntrl *Controller) DeleteLyricsVariant(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	lang, ok := parseLang(c.Param("lang"))
+	if !ok || lang == "" {
+		exceptions.InvalidLanguageError(c)
+		return
+	}
+
+	err := cntrl.lyricsService.DeleteVariant(c.Request.Context(), id, lang)
+	switch {
+	case errors.Is(err, models.ErrLyricsVariantNotFound):
+		exceptions.LyricsVariantNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("delete lyrics variant error: %s", err)
+		exceptions.DeletingLyricsVariantError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// localizeSong подменяет текст песни вариантом на языке из lang или Accept-Language.
+// При ошибке отвечает сам и возвращает false
+func (cntrl *Controller) localizeSong(c *gin.Context, song *models.Song) (*models.Song, bool) {
+	prefs, ok := parseLangPrefs(c)
+	if !ok {
+		exceptions.InvalidLanguageError(c)
+		return nil, false
+	}
+
+	c.Header("Vary", "Accept-Language")
+
+	localized, err := cntrl.lyricsService.Localize(c.Request.Context(), song, prefs)
+	if err != nil {
+		logrus.Debugf("localize song error: %s", err)
+		exceptions.FetchingLyricsError(c)
+		return nil, false
+	}
+
+	if localized.LyricsLang != "" {
+		c.Header("Content-Language", localized.LyricsLang)
+	}
+
+	return localized, true
+}
+
+// readLrc читает файл из поля file формы или, если запрос не multipart, все тело запроса
+func readLrc(c *gin.Context) (string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxLrcSize)
+
+	body := c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return "", err
+		}
+
+		opened, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer opened.Close()
+
+		body = opened
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+
+	if !utf8.Valid(data) {
+		return "", errors.New("lrc is not valid utf-8")
+	}
+
+	return string(data), nil
+}
+
+// handleLyricsError отвечает ошибкой и возвращает false, если err не nil
+func handleLyricsError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+	case errors.Is(err, models.ErrSyncedLyricsNotFound):
+		exceptions.SyncedLyricsNotFoundError(c)
+	default:
+		logrus.Debugf("get song lyrics error: %s", err)
+		exceptions.FetchingLyricsError(c)
+	}
+
+	return false
+}
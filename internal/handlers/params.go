@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"golang.org/x/text/language"
+)
+
+// parseIdParam читает числовой path-параметр, при ошибке прерывает запрос переданными исключениями
+func parseIdParam(c *gin.Context, name string, notProvided func(*gin.Context), failedToParse func(*gin.Context)) (int64, bool) {
+	id := c.Param(name)
+	if id == "" {
+		notProvided(c)
+		return 0, false
+	}
+
+	intId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		failedToParse(c)
+		return 0, false
+	}
+
+	return intId, true
+}
+
+// parsePositiveQuery читает положительное целое из query: без значения - def, больше max - max
+func parsePositiveQuery(c *gin.Context, name string, def int, max int) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return def, true
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+
+	return min(n, max), true
+}
+
+// parsePagination читает limit и page из query, при некорректных значениях отвечает 400.
+// Большие значения уменьшаются: limit - до maxPageLimit, page - чтобы смещение страницы не переполнялось
+func parsePagination(c *gin.Context) (limit int, page int, ok bool) {
+	limit, ok = parsePositiveQuery(c, "limit", defaultSongsLimit, maxPageLimit)
+	if !ok {
+		exceptions.InvalidPaginationError(c)
+		return 0, 0, false
+	}
+
+	page, ok = parsePositiveQuery(c, "page", defaultPage, math.MaxInt32)
+	if !ok {
+		exceptions.InvalidPaginationError(c)
+		return 0, 0, false
+	}
+
+	return limit, page, true
+}
+
+// nonEmpty отбрасывает пустые значения, например из "?tag=&tag=live"
+func nonEmpty(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// parseOptionalTime разбирает значение по первому подходящему формату, пустое значение - nil
+func parseOptionalTime(value string, layouts ...string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range layouts {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			return &parsed, nil
+		}
+	}
+
+	return nil, errors.New("invalid time value: " + value)
+}
+
+func parseOptionalBool(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
+
+// parseLang приводит тег языка BCP 47 к каноническому виду (EN-us -> en-US), пустое значение - язык не задан
+func parseLang(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", true
+	}
+
+	tag, err := language.Parse(value)
+	if err != nil || tag == language.Und {
+		return "", false
+	}
+
+	return tag.String(), true
+}
+
+// parseLangPrefs возвращает языки текста песни в порядке предпочтения: lang из query или заголовок Accept-Language.
+// Некорректный Accept-Language игнорируется, некорректный lang - ошибка запроса
+func parseLangPrefs(c *gin.Context) ([]language.Tag, bool) {
+	if lang := c.Query("lang"); lang != "" {
+		tag, err := language.Parse(lang)
+		if err != nil || tag == language.Und {
+			return nil, false
+		}
+
+		return []language.Tag{tag}, true
+	}
+
+	prefs, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	if err != nil {
+		return nil, true
+	}
+
+	return prefs, true
+}
+
+// songsPath - путь ресурса песен, от него строится Location
+const songsPath = "/api/v1/songs"
+
+// songLocation - путь песни для заголовка Location
+func songLocation(id int64) string {
+	return songsPath + "/" + strconv.FormatInt(id, 10)
+}
+
+// songETag - ETag песни, строгий тег из ее версии
+func songETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// parseIfMatch возвращает версии песни из If-Match. nil - заголовка нет или он равен *, то есть подходит любая версия.
+// Слабые и чужие теги не совпадают ни с одной версией, и список версий остается пустым
+func parseIfMatch(c *gin.Context) []int {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := make([]int, 0, 1)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+
+		version, err := strconv.Atoi(tag[1 : len(tag)-1])
+		if err == nil && version > 0 {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+type PlaylistPayload struct {
+	Name string `json:"name"`
+}
+
+type PlaylistEntryPayload struct {
+	SongId   int64 `json:"song_id"`
+	Position int   `json:"position"`
+}
+
+type PlaylistEntryMovePayload struct {
+	Position int `json:"position"`
+}
+
+// GetPlaylistsWithPagination godoc
+// @Summary Получение всех плейлистов с пагинацией
+// @Description Возвращает список плейлистов без песен, новые плейлисты первыми
+// @Tags playlists
+// @Produce  json
+// @Param   page              query     int        false   "Страница, по умолчанию 1" minimum(1)
+// @Param   limit             query     int        false   "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.PlaylistsWithPagination "Успешный ответ"
+// @Failure 400 {object} exceptions.Error                 "Неверный запрос, некорректные параметры пагинации"
+// @Failure 500 {object} exceptions.Error                 "Внутренняя ошибка сервера"
+// @Router  /playlists [get]
+func (cntrl *Controller) GetPlaylistsWithPagination(c *gin.Context) {
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	playlists, err := cntrl.playlistService.GetAllPlaylistsWithPagination(c.Request.Context(), limit, page)
+	if err != nil {
+		logrus.Debugf("get all playlists with pagination error: %s", err)
+		exceptions.FetchingPlaylistsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, playlists)
+}
+
+// GetPlaylistById godoc
+// @Summary Получение плейлиста с песнями
+// @Description Возвращает плейлист и страницу его записей в порядке позиций. Записи с песнями из корзины скрыты, позиции нумеруются подряд среди видимых записей
+// @Tags playlists
+// @Produce json
+// @Param   id                path      int        true    "ID плейлиста"
+// @Param   page              query     int        false   "Страница, по умолчанию 1" minimum(1)
+// @Param   limit             query     int        false   "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.PlaylistWithPagination "Плейлист успешно найден"
+// @Failure 400 {object} exceptions.Error                "Неверный запрос, ID плейлиста или параметры пагинации некорректны"
+// @Failure 404 {object} exceptions.Error                "Плейлист с предоставленным ID не найден"
+// @Failure 500 {object} exceptions.Error                "Внутренняя ошибка сервера"
+// @Router  /playlists/{id} [get]
+func (cntrl *Controller) GetPlaylistById(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.PlaylistIdIsNotProvidedError, exceptions.FailedToParsePlaylistIdError)
+	if !ok {
+		return
+	}
+
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	playlist, err := cntrl.playlistService.GetPlaylistWithPagination(c.Request.Context(), id, limit, page)
+	if errors.Is(err, models.ErrPlaylistNotFound) {
+		exceptions.PlaylistByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("get playlist by id error: %s", err)
+		exceptions.FetchingPlaylistsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// AddPlaylist godoc
+// @Summary Создание плейлиста
+// @Description Создает новый пустой плейлист
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param playlist body PlaylistPayload true "Данные плейлиста"
+// @Success 201 {object} models.Playlist  "Плейлист успешно создан"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /playlists [post]
+func (cntrl *Controller) AddPlaylist(c *gin.Context) {
+	var payload PlaylistPayload
+	err := c.BindJSON(&payload)
+	if err != nil || strings.TrimSpace(payload.Name) == "" {
+		exceptions.InvalidPayloadToSaveAPlaylistError(c)
+		return
+	}
+
+	playlist := models.Playlist{
+		Name: strings.TrimSpace(payload.Name),
+	}
+
+	err = cntrl.playlistService.AddPlaylist(c.Request.Context(), &playlist)
+	if err != nil {
+		logrus.Debugf("add playlist error: %s", err)
+		exceptions.CreatingPlaylistError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, playlist)
+}
+
+// RenamePlaylist godoc
+// @Summary Переименование плейлиста
+// @Description Меняет название плейлиста
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param  id        path  int              true  "ID плейлиста"
+// @Param  playlist  body  PlaylistPayload  true  "Новое название плейлиста"
+// @Success 200 {object} models.Playlist  "Плейлист успешно переименован"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Плейлист с предоставленным ID не найден"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [patch]
+func (cntrl *Controller) RenamePlaylist(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.PlaylistIdIsNotProvidedError, exceptions.FailedToParsePlaylistIdError)
+	if !ok {
+		return
+	}
+
+	var payload PlaylistPayload
+	err := c.BindJSON(&payload)
+	if err != nil || strings.TrimSpace(payload.Name) == "" {
+		exceptions.InvalidPayloadToSaveAPlaylistError(c)
+		return
+	}
+
+	playlist, err := cntrl.playlistService.RenamePlaylist(c.Request.Context(), id, strings.TrimSpace(payload.Name))
+	if errors.Is(err, models.ErrPlaylistNotFound) {
+		exceptions.PlaylistByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("rename playlist error: %s", err)
+		exceptions.UpdatingPlaylistError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, playlist)
+}
+
+// DeletePlaylist godoc
+// @Summary Удаление плейлиста
+// @Description Удаляет плейлист вместе с его записями, песни остаются в библиотеке
+// @Tags playlists
+// @Produce  json
+// @Param  id  path  int  true  "ID плейлиста"
+// @Success 204 {object} any              "Плейлист успешно удален"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Плейлист с предоставленным ID не найден"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /playlists/{id} [delete]
+func (cntrl *Controller) DeletePlaylist(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.PlaylistIdIsNotProvidedError, exceptions.FailedToParsePlaylistIdError)
+	if !ok {
+		return
+	}
+
+	err := cntrl.playlistService.DeletePlaylist(c.Request.Context(), id)
+	if errors.Is(err, models.ErrPlaylistNotFound) {
+		exceptions.PlaylistByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("delete playlist error: %s", err)
+		exceptions.DeletingPlaylistError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddPlaylistEntry godoc
+// @Summary Добавление песни в плейлист
+// @Description Вставляет песню на позицию position (с 1), последующие записи сдвигаются. Без position песня добавляется в конец. Позиция считается среди видимых записей
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param  id     path  int                   true  "ID плейлиста"
+// @Param  entry  body  PlaylistEntryPayload  true  "Песня и позиция"
+// @Success 201 {object} models.PlaylistEntry "Песня добавлена в плейлист"
+// @Failure 400 {object} exceptions.Error     "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error     "Плейлист или песня не найдены"
+// @Failure 500 {object} exceptions.Error     "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries [post]
+func (cntrl *Controller) AddPlaylistEntry(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.PlaylistIdIsNotProvidedError, exceptions.FailedToParsePlaylistIdError)
+	if !ok {
+		return
+	}
+
+	var payload PlaylistEntryPayload
+	err := c.BindJSON(&payload)
+	if err != nil || payload.SongId < 1 || payload.Position < 0 {
+		exceptions.InvalidPayloadToSaveAPlaylistError(c)
+		return
+	}
+
+	entry, err := cntrl.playlistService.AddPlaylistEntry(c.Request.Context(), id, payload.SongId, payload.Position)
+	switch {
+	case errors.Is(err, models.ErrPlaylistNotFound):
+		exceptions.PlaylistByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrPlaylistSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("add playlist entry error: %s", err)
+		exceptions.UpdatingPlaylistError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// MovePlaylistEntry godoc
+// @Summary Перемещение песни внутри плейлиста
+// @Description Переносит запись на позицию position, остальные записи сдвигаются. Позиция за пределами плейлиста приводится к первой или последней. Позиция считается среди видимых записей, запись с песней из корзины считается не найденной
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param  id        path  int                       true  "ID плейлиста"
+// @Param  entry_id  path  int                       true  "ID записи плейлиста"
+// @Param  entry     body  PlaylistEntryMovePayload  true  "Новая позиция"
+// @Success 204 {object} any              "Запись перемещена"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Плейлист или запись не найдены"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries/{entry_id} [patch]
+func (cntrl *Controller) MovePlaylistEntry(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.PlaylistIdIsNotProvidedError, exceptions.FailedToParsePlaylistIdError)
+	if !ok {
+		return
+	}
+
+	entryId, ok := parseIdParam(c, "entry_id", exceptions.PlaylistEntryIdIsNotProvidedError, exceptions.FailedToParsePlaylistEntryIdError)
+	if !ok {
+		return
+	}
+
+	var payload PlaylistEntryMovePayload
+	err := c.BindJSON(&payload)
+	if err != nil || payload.Position < 1 {
+		exceptions.InvalidPayloadToSaveAPlaylistError(c)
+		return
+	}
+
+	err = cntrl.playlistService.MovePlaylistEntry(c.Request.Context(), id, entryId, payload.Position)
+	switch {
+	case errors.Is(err, models.ErrPlaylistNotFound):
+		exceptions.PlaylistByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrPlaylistEntryNotFound):
+		exceptions.PlaylistEntryNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("move playlist entry error: %s", err)
+		exceptions.UpdatingPlaylistError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemovePlaylistEntry godoc
+// @Summary Удаление песни из плейлиста
+// @Description Удаляет запись из плейлиста, последующие записи сдвигаются, сама песня остается в библиотеке
+// @Tags playlists
+// @Produce  json
+// @Param  id        path  int  true  "ID плейлиста"
+// @Param  entry_id  path  int  true  "ID записи плейлиста"
+// @Success 204 {object} any              "Запись удалена"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Плейлист или запись не найдены"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /playlists/{id}/entries/{entry_id} [delete]
+func (cntrl *Controller) RemovePlaylistEntry(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.PlaylistIdIsNotProvidedError, exceptions.FailedToParsePlaylistIdError)
+	if !ok {
+		return
+	}
+
+	entryId, ok := parseIdParam(c, "entry_id", exceptions.PlaylistEntryIdIsNotProvidedError, exceptions.FailedToParsePlaylistEntryIdError)
+	if !ok {
+		return
+	}
+
+	err := cntrl.playlistService.RemovePlaylistEntry(c.Request.Context(), id, entryId)
+	switch {
+	case errors.Is(err, models.ErrPlaylistNotFound):
+		exceptions.PlaylistByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrPlaylistEntryNotFound):
+		exceptions.PlaylistEntryNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("remove playlist entry error: %s", err)
+		exceptions.UpdatingPlaylistError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
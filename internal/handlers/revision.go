@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+// GetSongRevisions godoc
+// @Summary Получение истории правок песни
+// @Description Возвращает ревизии песни от последней к первой: действие, автор (заголовок X-Actor), изменившиеся поля. История удаленной песни тоже доступна
+// @Tags songs
+// @Produce  json
+// @Param  id     path   int  true   "ID песни"
+// @Param  page   query  int  false  "Страница, по умолчанию 1" minimum(1)
+// @Param  limit  query  int  false  "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.SongRevisionsWithPagination "Успешный ответ"
+// @Failure 400 {object} exceptions.Error                     "Неверный запрос, ID песни или параметры пагинации некорректны"
+// @Failure 404 {object} exceptions.Error                     "У песни нет истории правок"
+// @Failure 500 {object} exceptions.Error                     "Внутренняя ошибка сервера"
+// @Router /songs/{id}/revisions [get]
+func (cntrl *Controller) GetSongRevisions(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := cntrl.revisionService.GetRevisions(c.Request.Context(), id, limit, page)
+	if errors.Is(err, models.ErrSongNotFound) {
+		exceptions.SongByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("get song revisions error: %s", err)
+		exceptions.FetchingSongRevisionsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetSongRevision godoc
+// @Summary Получение ревизии песни
+// @Description Возвращает ревизию со снимком полей песни и отличия от предыдущей ревизии, для текста - построчно
+// @Tags songs
+// @Produce  json
+// @Param  id   path  int  true  "ID песни"
+// @Param  rev  path  int  true  "Номер ревизии"
+// @Success 200 {object} services.SongRevisionDiff "Успешный ответ"
+// @Failure 400 {object} exceptions.Error          "Неверный запрос, ID песни или номер ревизии некорректен"
+// @Failure 404 {object} exceptions.Error          "Ревизия не найдена"
+// @Failure 500 {object} exceptions.Error          "Внутренняя ошибка сервера"
+// @Router /songs/{id}/revisions/{rev} [get]
+func (cntrl *Controller) GetSongRevision(c *gin.Context) {
+	id, rev, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	diff, err := cntrl.revisionService.GetRevisionDiff(c.Request.Context(), id, rev)
+	if errors.Is(err, models.ErrSongRevisionNotFound) {
+		exceptions.SongRevisionNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("get song revision error: %s", err)
+		exceptions.FetchingSongRevisionsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreSongRevision godoc
+// @Summary Восстановление песни из ревизии
+// @Description Возвращает песне название, исполнителя, текст, ссылку, дату выпуска и язык из ревизии. Восстановление записывается новой ревизией
+// @Tags songs
+// @Produce  json
+// @Param  id        path    int     true   "ID песни"
+// @Param  rev       path    int     true   "Номер ревизии"
+// @Param  If-Match  header  string  false  "ETag песни из GET /songs/{id}"
+// @Success 200 {object} models.Song      "Песня восстановлена"
+// @Header  200 {string} ETag             "Новая версия песни"
+// @Failure 400 {object} exceptions.Error "Неверный запрос, ID песни или номер ревизии некорректен"
+// @Failure 404 {object} exceptions.Error "Песня или ревизия не найдены"
+// @Failure 409 {object} exceptions.Error "У исполнителя уже есть другая песня с названием из ревизии, ее путь в Location"
+// @Failure 412 {object} exceptions.Error "Песня изменена, версия не совпадает с If-Match"
+// @Failure 422 {object} exceptions.Error "Поля ревизии не проходят текущую валидацию песни (нарушения в details)"
+// @Failure 428 {object} exceptions.Error "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/revisions/{rev}/restore [post]
+func (cntrl *Controller) RestoreSongRevision(c *gin.Context) {
+	id, rev, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	song, err := cntrl.revisionService.RestoreRevision(c.Request.Context(), id, rev, parseIfMatch(c))
+	switch {
+	case errors.Is(err, models.ErrSongRevisionNotFound):
+		exceptions.SongRevisionNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrSongVersionMismatch):
+		exceptions.SongVersionMismatchError(c)
+		return
+	case validationFailed(c, err), songAlreadyExists(c, err):
+		return
+	case err != nil:
+		logrus.Debugf("restore song revision error: %s", err)
+		exceptions.RestoringSongRevisionError(c)
+		return
+	}
+
+	c.Header("ETag", songETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
+
+func parseRevisionParams(c *gin.Context) (int64, int, bool) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return 0, 0, false
+	}
+
+	rev, ok := parseIdParam(c, "rev", exceptions.FailedToParseRevisionError, exceptions.FailedToParseRevisionError)
+	if !ok {
+		return 0, 0, false
+	}
+
+	if rev < 1 || rev > math.MaxInt32 {
+		exceptions.FailedToParseRevisionError(c)
+		return 0, 0, false
+	}
+
+	return id, int(rev), true
+}
@@ -1,25 +1,50 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/internal/services"
 	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/shlmvgleb/em-task/pkg/jsonpatch"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	defaultSongsLimit = 10
 	defaultPage       = 1
+	// больше элементов на странице не отдается, большее значение limit уменьшается до него
+	maxPageLimit = 100
 
-	defaultVersePage = 1
+	defaultVersePage     = 1
+	defaultVersesPerPage = 1
+	maxVersesPerPage     = 100
+
+	paginationByPage   = "page"
+	paginationByCursor = "cursor"
+
+	defaultSuggestionsLimit = 10
+	maxSuggestionsLimit     = 50
+
+	maxLyricsDistance = 100
+
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+	// ограничение размера тела PATCH и PUT песни
+	maxSongDocumentSize = 1 << 20
 )
 
 type AddSongPayload struct {
-	Group string `json:"group"`
-	Song  string `json:"song"`
+	Group string `json:"group" maxLength:"255"`
+	Song  string `json:"song" maxLength:"255"`
 }
 
 // GetSongsWithPagination godoc
@@ -28,29 +53,86 @@ type AddSongPayload struct {
 // @Tags songs
 // @Accept  json
 // @Produce  json
-// @Param   page              query     int        false   "Страница"
-// @Param   limit             query     int        false   "Количество элементов"
-// @Param   search_query      query     string     false   "Полнотекстовый поиск по всем полям сущности Song"
+// @Param   page              query     int        false   "Страница, по умолчанию 1" minimum(1)
+// @Param   limit             query     int        false   "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Param   pagination        query     string     false   "page - по номеру страницы (по умолчанию), cursor - курсором, current_page и pages_amount в этом режиме не заполняются" Enums(page, cursor)
+// @Param   cursor            query     string     false   "next_cursor или prev_cursor из предыдущего ответа, включает пагинацию курсором. Курсор действует только с теми же фильтрами и сортировкой, иначе 400"
+// @Param   count             query     string     false   "Подсчет total_items и pages_amount: exact - точный (по умолчанию), estimate - оценка планировщика, none - без подсчета" Enums(exact, estimate, none)
+// @Param   search_query      query     string     false   "Полнотекстовый поиск по названию, исполнителю и тексту (в порядке убывания веса), совпадения возвращаются в highlight"
+// @Param   lang              query     string     false   "Язык полнотекстового поиска, тег BCP 47: запрос ищется в переводах на этот язык и в оригиналах с той же конфигурацией поиска"
+// @Param   fuzzy             query     string     false   "Нечеткий поиск по названию и исполнителю с учетом опечаток, совпавшее поле и сходство возвращаются в match"
+// @Param   group             query     string     false   "Исполнитель, без учета регистра"
+// @Param   group_match       query     string     false   "exact - имя целиком (по умолчанию), prefix - начало имени" Enums(exact, prefix)
+// @Param   song              query     string     false   "Название песни, без учета регистра"
+// @Param   song_match        query     string     false   "exact - название целиком (по умолчанию), prefix - начало названия" Enums(exact, prefix)
+// @Param   release_date_from query     string     false   "Дата выпуска не раньше, YYYY-MM-DD" format(date)
+// @Param   release_date_to   query     string     false   "Дата выпуска не позже, YYYY-MM-DD" format(date)
+// @Param   has_link          query     bool       false   "Наличие ссылки на песню"
+// @Param   created_after     query     string     false   "Добавлена после, RFC3339 или YYYY-MM-DD" format(date-time)
+// @Param   updated_after     query     string     false   "Изменена после, RFC3339 или YYYY-MM-DD" format(date-time)
+// @Param   genre             query     []string   false   "Жанр, включая поджанры. Можно передать несколько раз" collectionFormat(multi)
+// @Param   genre_match       query     string     false   "any - любой из жанров (по умолчанию), all - все жанры" Enums(any, all)
+// @Param   tag               query     []string   false   "Тег. Можно передать несколько раз" collectionFormat(multi)
+// @Param   tag_match         query     string     false   "any - любой из тегов (по умолчанию), all - все теги" Enums(any, all)
+// @Param   sort              query     string     false   "Ключи сортировки через запятую, '-' - по убыванию: release_date, created_at, updated_at, song, group, relevance (только с search_query), similarity (только с fuzzy). По умолчанию created_at, с fuzzy - -similarity"
+// @Param   facets            query     bool       false   "Добавить в ответ количество найденных песен по жанрам и тегам"
 // @Success 200 {object} []services.SongsWithPagination   "Успешный ответ, песня найдена"
-// @Failure 400 {object} exceptions.Error                 "Неверный запрос, ID песни не предоставлен или некорректен"
+// @Failure 400 {object} exceptions.Error                 "Неверный запрос, некорректные параметры фильтрации или пагинации"
 // @Failure 404 {object} exceptions.Error                 "Песня с предоставленным ID не найдена"
 // @Failure 500 {object} exceptions.Error                 "Внутренняя ошибка сервера"
 // @Router  /songs [get]
 func (cntrl *Controller) GetSongsWithPagination(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.Query("limit"))
-	page, _ := strconv.Atoi(c.Query("page"))
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
 
-	if limit == 0 {
-		limit = defaultSongsLimit
+	filter, ok := parseSongFilter(c)
+	if !ok {
+		exceptions.InvalidSongsFilterError(c)
+		return
 	}
 
-	if page == 0 {
-		page = defaultPage
+	sort, ok := parseSongSort(c.Query("sort"), filter)
+	if !ok {
+		exceptions.InvalidSongsSortError(c)
+		return
 	}
 
-	searchQuery := c.Query("search_query")
-	songs, err := cntrl.songService.GetAllSongsWithPagination(c.Request.Context(), searchQuery, limit, page)
+	withFacets, err := strconv.ParseBool(c.DefaultQuery("facets", "false"))
 	if err != nil {
+		exceptions.InvalidSongsFilterError(c)
+		return
+	}
+
+	pagination := c.DefaultQuery("pagination", paginationByPage)
+	if pagination != paginationByPage && pagination != paginationByCursor {
+		exceptions.InvalidSongsFilterError(c)
+		return
+	}
+
+	count := models.SongCount(c.DefaultQuery("count", string(models.CountExact)))
+	if count != models.CountExact && count != models.CountEstimate && count != models.CountNone {
+		exceptions.InvalidSongsFilterError(c)
+		return
+	}
+
+	var songs *services.SongsWithPagination
+	token := c.Query("cursor")
+	if pagination == paginationByCursor || token != "" {
+		songs, err = cntrl.songService.GetAllSongsWithCursor(c.Request.Context(), filter, sort, token, count, withFacets, limit)
+	} else {
+		songs, err = cntrl.songService.GetAllSongsWithPagination(c.Request.Context(), filter, sort, count, withFacets, limit, page)
+	}
+
+	switch {
+	case errors.Is(err, models.ErrInvalidSongSort):
+		exceptions.InvalidSongsSortError(c)
+		return
+	case errors.Is(err, models.ErrInvalidSongCursor):
+		exceptions.InvalidSongsCursorError(c)
+		return
+	case err != nil:
 		logrus.Debugf("get all songs with pagination error: %s", err)
 		exceptions.FetchingSongsError(c)
 		return
@@ -59,65 +141,317 @@ func (cntrl *Controller) GetSongsWithPagination(c *gin.Context) {
 	c.JSON(http.StatusOK, songs)
 }
 
+// GetSongSuggestions godoc
+// @Summary Подсказки для поиска по мере ввода
+// @Description Возвращает песни, название или исполнитель которых начинаются с запроса или похожи на него с учетом опечаток. Совпадения по началу строки идут первыми
+// @Tags songs
+// @Produce  json
+// @Param   q      query     string  true    "Введенный текст"
+// @Param   limit  query     int     false   "Количество подсказок, по умолчанию 10, больше 50 уменьшается до 50" minimum(1) maximum(50)
+// @Success 200 {object} []models.SongSuggestion "Успешный ответ"
+// @Failure 400 {object} exceptions.Error        "Неверный запрос, пустой текст или некорректный limit"
+// @Failure 500 {object} exceptions.Error        "Внутренняя ошибка сервера"
+// @Router  /songs/suggest [get]
+func (cntrl *Controller) GetSongSuggestions(c *gin.Context) {
+	input := strings.TrimSpace(c.Query("q"))
+	if input == "" {
+		exceptions.InvalidSongSuggestQueryError(c)
+		return
+	}
+
+	limit, ok := parsePositiveQuery(c, "limit", defaultSuggestionsLimit, maxSuggestionsLimit)
+	if !ok {
+		exceptions.InvalidPaginationError(c)
+		return
+	}
+
+	suggestions, err := cntrl.songService.GetSongSuggestions(c.Request.Context(), input, limit)
+	if err != nil {
+		logrus.Debugf("get song suggestions error: %s", err)
+		exceptions.FetchingSongSuggestionsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// SearchSongLyrics godoc
+// @Summary Поиск по текстам песен с точностью до куплета
+// @Description Возвращает песни, в куплетах которых найден запрос, и номера этих куплетов. Номера куплетов можно передать как verses в /songs/paginated/{id}.
+// @Description С lang запрос разбирается конфигурацией поиска этого языка и ищется в переводах на этот язык (номера куплетов - куплеты перевода, см. lang в ответе)
+// @Description и в оригиналах песен без такого перевода, проиндексированных той же конфигурацией
+// @Tags songs
+// @Produce  json
+// @Param   q         query     string  true    "Запрос: слова, фраза в кавычках, -слово для исключения"
+// @Param   lang      query     string  false   "Язык поиска, тег BCP 47"
+// @Param   distance  query     int     false   "Все слова запроса должны быть в куплете не дальше стольких слов друг от друга (от 1 до 100), кавычки и исключения в этом режиме не поддерживаются"
+// @Param   page      query     int     false   "Страница, по умолчанию 1" minimum(1)
+// @Param   limit     query     int     false   "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.LyricsMatchesWithPagination "Успешный ответ"
+// @Failure 400 {object} exceptions.Error                      "Неверный запрос, пустой запрос, некорректное расстояние или пагинация"
+// @Failure 500 {object} exceptions.Error                      "Внутренняя ошибка сервера"
+// @Router  /songs/search/lyrics [get]
+func (cntrl *Controller) SearchSongLyrics(c *gin.Context) {
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	query := models.LyricsQuery{
+		Text: strings.TrimSpace(c.Query("q")),
+	}
+
+	if distance := c.Query("distance"); distance != "" {
+		var err error
+		query.Distance, err = strconv.Atoi(distance)
+		if err != nil || query.Distance < 1 || query.Distance > maxLyricsDistance {
+			exceptions.InvalidLyricsSearchQueryError(c)
+			return
+		}
+	}
+
+	if query.Text == "" {
+		exceptions.InvalidLyricsSearchQueryError(c)
+		return
+	}
+
+	query.Lang, ok = parseLang(c.Query("lang"))
+	if !ok {
+		exceptions.InvalidLanguageError(c)
+		return
+	}
+
+	matches, err := cntrl.songService.SearchLyrics(c.Request.Context(), query, limit, page)
+	if err != nil {
+		logrus.Debugf("search lyrics error: %s", err)
+		exceptions.SearchingLyricsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, matches)
+}
+
+// parseSongFilter читает фильтры списка песен из query-параметров, false - если какой-то из них некорректен
+func parseSongFilter(c *gin.Context) (models.SongFilter, bool) {
+	filter := models.SongFilter{
+		SearchQuery: c.Query("search_query"),
+		Fuzzy:       strings.TrimSpace(c.Query("fuzzy")),
+		Group:       strings.TrimSpace(c.Query("group")),
+		GroupMatch:  models.TextMatch(c.DefaultQuery("group_match", string(models.MatchExact))),
+		Song:        strings.TrimSpace(c.Query("song")),
+		SongMatch:   models.TextMatch(c.DefaultQuery("song_match", string(models.MatchExact))),
+		Genres:      nonEmpty(c.QueryArray("genre")),
+		GenreMatch:  models.ClassificationMatch(c.DefaultQuery("genre_match", string(models.MatchAny))),
+		Tags:        nonEmpty(c.QueryArray("tag")),
+		TagMatch:    models.ClassificationMatch(c.DefaultQuery("tag_match", string(models.MatchAny))),
+	}
+
+	for _, match := range []models.TextMatch{filter.GroupMatch, filter.SongMatch} {
+		if match != models.MatchExact && match != models.MatchPrefix {
+			return filter, false
+		}
+	}
+
+	for _, match := range []models.ClassificationMatch{filter.GenreMatch, filter.TagMatch} {
+		if match != models.MatchAny && match != models.MatchAll {
+			return filter, false
+		}
+	}
+
+	var err error
+	filter.ReleaseDateFrom, err = parseOptionalTime(c.Query("release_date_from"), time.DateOnly)
+	if err != nil {
+		return filter, false
+	}
+
+	filter.ReleaseDateTo, err = parseOptionalTime(c.Query("release_date_to"), time.DateOnly)
+	if err != nil {
+		return filter, false
+	}
+
+	filter.CreatedAfter, err = parseOptionalTime(c.Query("created_after"), time.RFC3339, time.DateOnly)
+	if err != nil {
+		return filter, false
+	}
+
+	filter.UpdatedAfter, err = parseOptionalTime(c.Query("updated_after"), time.RFC3339, time.DateOnly)
+	if err != nil {
+		return filter, false
+	}
+
+	filter.HasLink, err = parseOptionalBool(c.Query("has_link"))
+	if err != nil {
+		return filter, false
+	}
+
+	var ok bool
+	filter.Lang, ok = parseLang(c.Query("lang"))
+	if !ok {
+		return filter, false
+	}
+
+	return filter, true
+}
+
+// parseSongSort разбирает "release_date,-created_at": поля из белого списка без повторов, '-' - по убыванию
+func parseSongSort(raw string, filter models.SongFilter) ([]models.SongSortKey, bool) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, true
+	}
+
+	keys := make([]models.SongSortKey, 0)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		key := models.SongSortKey{
+			Field: models.SongSortField(strings.TrimPrefix(part, "-")),
+			Desc:  strings.HasPrefix(part, "-"),
+		}
+
+		if !slices.Contains(models.SongSortFields, key.Field) {
+			return nil, false
+		}
+
+		if key.Field == models.SortByRelevance && filter.SearchQuery == "" {
+			return nil, false
+		}
+
+		if key.Field == models.SortBySimilarity && filter.Fuzzy == "" {
+			return nil, false
+		}
+
+		for _, prev := range keys {
+			if prev.Field == key.Field {
+				return nil, false
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, true
+}
+
 // GetSongByIdWithVersePagination godoc
 // @Summary Получение песни по ID с пагинацией по куплетам
-// @Description Возвращает информацию о песне по указанному ID с поддержкой пагинации (если она нужна).
+// @Description Возвращает песню по указанному ID и ее куплеты: страницу page по per_page куплетов или диапазон позиций verses (например, 2-4 или 3).
+// @Description Текст песни не возвращается, выбранные куплеты приходят списком verses с позициями.
 // @Tags songs
 // @Accept  json
 // @Produce  json
-// @Param   id      path     int     true    "ID песни"
-// @Param   page    query    int     false   "Страница(порядковый номер куплета)"
+// @Param   id        path     int     true    "ID песни"
+// @Param   page      query    int     false   "Страница, по умолчанию 1"
+// @Param   per_page  query    int     false   "Количество куплетов на странице, по умолчанию 1"
+// @Param   verses    query    string  false   "Диапазон позиций куплетов, например 2-4; вместе с page и per_page не используется"
+// @Param   lang      query    string  false   "Язык текста, тег BCP 47; заменяет Accept-Language. Куплеты перевода не имеют id"
+// @Param   Accept-Language  header  string  false  "Предпочтительные языки текста"
 // @Success 200 {object} services.SongByIdWithVersePagination  "Песня успешно найдена"
-// @Failure 400 {object} exceptions.Error                      "Неверный запрос, ID песни не предоставлен или некорректен"
-// @Failure 404 {object} exceptions.Error                      "Песня с предоставленным ID не найдена"
+// @Failure 400 {object} exceptions.Error                      "Неверный запрос, ID песни или выбор куплетов некорректен"
+// @Failure 404 {object} exceptions.Error                      "Песня или выбранные куплеты не найдены"
 // @Failure 500 {object} exceptions.Error                      "Внутренняя ошибка сервера"
 // @Router  /songs/paginated/{id} [get]
 func (cntrl *Controller) GetSongByIdWithVersePagination(c *gin.Context) {
-	id := c.Param("id")
-	if id == "" {
-		exceptions.SongIdIsNotProvidedError(c)
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
 		return
 	}
 
-	intId, err := strconv.ParseInt(id, 10, 64)
-	if err != nil {
-		exceptions.FailedToParseSongIdError(c)
+	selection, ok := parseVerseSelection(c)
+	if !ok {
+		exceptions.InvalidVerseSelectionError(c)
 		return
 	}
 
-	page, _ := strconv.Atoi(c.Query("page"))
-	if page == 0 {
-		page = defaultVersePage
-	}
-
-	song, err := cntrl.songService.GetSongById(c.Request.Context(), intId)
+	song, err := cntrl.songService.GetSongById(c.Request.Context(), id)
 	if err != nil {
 		logrus.Debugf("get song by id error: %s", err)
 		exceptions.SongByIdNotFoundError(c)
 		return
 	}
 
-	paginated, err := cntrl.songService.CreateVersePagination(song, page)
-	if err != nil {
-		logrus.Debugf("create verse pagination error: %s", err)
+	song, ok = cntrl.localizeSong(c, song)
+	if !ok {
+		return
+	}
+
+	paginated, err := cntrl.songService.CreateVersePagination(c.Request.Context(), song, selection)
+	switch {
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrVerseNotFound):
 		exceptions.SongVerseNotFoundError(c)
 		return
+	case err != nil:
+		logrus.Debugf("create verse pagination error: %s", err)
+		exceptions.FetchingVersesError(c)
+		return
 	}
 
+	c.Header("ETag", songETag(paginated.Song.Version))
 	c.JSON(http.StatusOK, paginated)
 }
 
+// parseVerseSelection читает выбор куплетов: диапазон verses=2-4 (или один куплет verses=3) либо page и per_page
+func parseVerseSelection(c *gin.Context) (services.VerseSelection, bool) {
+	raw := strings.TrimSpace(c.Query("verses"))
+	if raw != "" {
+		if c.Query("page") != "" || c.Query("per_page") != "" {
+			return services.VerseSelection{}, false
+		}
+
+		first, last, isRange := strings.Cut(raw, "-")
+		if !isRange {
+			last = first
+		}
+
+		from, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil {
+			return services.VerseSelection{}, false
+		}
+
+		to, err := strconv.Atoi(strings.TrimSpace(last))
+		if err != nil || from < 1 || to < from || to-from+1 > maxVersesPerPage {
+			return services.VerseSelection{}, false
+		}
+
+		return services.VerseSelection{From: from, To: to}, true
+	}
+
+	selection := services.VerseSelection{Page: defaultVersePage, PerPage: defaultVersesPerPage}
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return services.VerseSelection{}, false
+		}
+		selection.Page = page
+	}
+
+	if value := c.Query("per_page"); value != "" {
+		perPage, err := strconv.Atoi(value)
+		if err != nil || perPage < 1 || perPage > maxVersesPerPage {
+			return services.VerseSelection{}, false
+		}
+		selection.PerPage = perPage
+	}
+
+	return selection, true
+}
+
 // GetSongById godoc
 // @Summary Получение песни по ID
-// @Description Возвращает информацию о песне по указанному ID
+// @Description Возвращает информацию о песне по указанному ID. Текст песни выбирается по lang или Accept-Language:
+// @Description перевод на предпочтительном языке, иначе оригинал. Язык выбранного перевода - lyrics_lang и заголовок Content-Language.
 // @Tags songs
 // @Accept  json
 // @Produce json
-// @Param   id      path     int     true    "ID песни"
-// @Success 200 {object} services.SongByIdWithVersePagination  "Песня успешно найдена"
-// @Failure 400 {object} exceptions.Error                      "Неверный запрос, ID песни не предоставлен или некорректен"
-// @Failure 404 {object} exceptions.Error                      "Песня с предоставленным ID не найдена"
-// @Failure 500 {object} exceptions.Error                      "Внутренняя ошибка сервера"
+// @Param   id               path     int     true    "ID песни"
+// @Param   lang             query    string  false   "Язык текста, тег BCP 47; заменяет Accept-Language"
+// @Param   Accept-Language  header   string  false   "Предпочтительные языки текста"
+// @Success 200 {object} models.Song       "Песня успешно найдена"
+// @Header  200 {string} ETag              "Версия песни для If-Match"
+// @Failure 400 {object} exceptions.Error  "Неверный запрос, ID песни не предоставлен или некорректен"
+// @Failure 404 {object} exceptions.Error  "Песня с предоставленным ID не найдена"
+// @Failure 500 {object} exceptions.Error  "Внутренняя ошибка сервера"
 // @Router  /songs/{id} [get]
 func (cntrl *Controller) GetSongById(c *gin.Context) {
 	id := c.Param("id")
@@ -139,18 +473,34 @@ func (cntrl *Controller) GetSongById(c *gin.Context) {
 		return
 	}
 
+	song, ok := cntrl.localizeSong(c, song)
+	if !ok {
+		return
+	}
+
+	c.Header("ETag", songETag(song.Version))
 	c.JSON(http.StatusOK, song)
 }
 
 // AddSong godoc
 // @Summary Добавление новой песни
-// @Description Создает новую запись о песне
+// @Description Создает новую запись о песне, детали (текст, ссылка, дата выпуска) заполняются асинхронно из внешнего API.
+// @Description У исполнителя не может быть двух песен с одинаковым названием без учета регистра и пробелов.
+// @Description Запрос с Idempotency-Key выполняется один раз: повтор с тем же ключом и телом в течение IDEMPOTENCY_KEY_TTL
+// @Description получает сохраненный ответ с заголовком Idempotent-Replayed: true
 // @Tags songs
 // @Accept  json
 // @Produce  json
-// @Param song body AddSongPayload true "Данные песни для добавления"
-// @Success 201 {object} any              "Песня успешно добавлена"
-// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Param song            body    AddSongPayload  true   "Данные песни для добавления"
+// @Param Idempotency-Key header  string          false  "Ключ идемпотентности, до 255 печатных символов ASCII"
+// @Success 201 {object} models.Song      "Песня успешно добавлена, обогащение поставлено в очередь"
+// @Header  201 {string} Location         "Путь добавленной песни"
+// @Header  201 {string} ETag             "Версия песни"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных или Idempotency-Key"
+// @Failure 409 {object} exceptions.Error "Песня уже есть у исполнителя (ее путь в Location) или запрос с этим Idempotency-Key еще выполняется"
+// @Header  409 {string} Location         "Путь существующей песни"
+// @Failure 413 {object} exceptions.Error "Тело запроса с Idempotency-Key больше 1 МБ"
+// @Failure 422 {object} exceptions.Error "Поля песни не прошли валидацию (нарушения в details) или Idempotency-Key использован с другим запросом"
 // @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
 // @Router /songs [post]
 func (cntrl *Controller) AddSong(c *gin.Context) {
@@ -161,41 +511,71 @@ func (cntrl *Controller) AddSong(c *gin.Context) {
 		return
 	}
 
-	details, err := cntrl.songDetailsApiService.FindSongDetails(c.Request.Context(), payload.Group, payload.Song)
-	if err != nil {
-		logrus.Debugf("find song details error: %s", err)
-		exceptions.FindSongDetailsError(c)
-		return
-	}
-
 	song := models.Song{
-		Group:       payload.Group,
-		Song:        payload.Song,
-		Text:        details.Text,
-		Link:        details.Link,
-		ReleaseDate: details.ReleaseDate,
+		Group:            payload.Group,
+		Song:             payload.Song,
+		EnrichmentStatus: models.EnrichmentPending,
 	}
 
 	err = cntrl.songService.AddSong(c.Request.Context(), &song)
+	if validationFailed(c, err) || songAlreadyExists(c, err) {
+		return
+	}
 	if err != nil {
 		logrus.Debugf("add song error: %s", err)
 		exceptions.CreatingSongError(c)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.Header("Location", songLocation(song.Id))
+	c.Header("ETag", songETag(song.Version))
+	c.JSON(http.StatusCreated, song)
+}
+
+// GetSongEnrichment godoc
+// @Summary Статус обогащения песни
+// @Description Возвращает статус заполнения деталей песни из внешнего API и последнюю задачу обогащения
+// @Tags songs
+// @Produce json
+// @Param   id      path     int     true    "ID песни"
+// @Success 200 {object} services.SongEnrichment  "Статус обогащения"
+// @Failure 400 {object} exceptions.Error         "Неверный запрос, ID песни не предоставлен или некорректен"
+// @Failure 404 {object} exceptions.Error         "Песня с предоставленным ID не найдена"
+// @Router  /songs/{id}/enrichment [get]
+func (cntrl *Controller) GetSongEnrichment(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	enrichment, err := cntrl.enrichmentService.GetSongEnrichment(c.Request.Context(), id)
+	if err != nil {
+		logrus.Debugf("get song enrichment error: %s", err)
+		exceptions.SongByIdNotFoundError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrichment)
 }
 
 // UpdateSong godoc
 // @Summary Обновление данных песни
-// @Description Обновляет запись о песне
+// @Description Устарел, используйте PATCH и PUT /songs/{id}. Переносит в песню непустые поля, очистить поле нельзя. С заголовком If-Match песня обновляется, только если ее версия совпадает с переданным ETag
 // @Tags songs
 // @Accept  json
 // @Produce  json
-// @Param song body models.Song true "Данные песни для обновления"
+// @Param song      body    models.Song  true   "Данные песни для обновления"
+// @Param If-Match  header  string       false  "ETag песни из GET /songs/{id}"
 // @Success 201 {object} models.Song "Песня успешно обновлена"
+// @Header  201 {string} ETag "Новая версия песни"
 // @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена"
+// @Failure 409 {object} exceptions.Error "У исполнителя уже есть песня с таким названием, ее путь в Location"
+// @Failure 412 {object} exceptions.Error "Песня изменена, версия не совпадает с If-Match"
+// @Failure 422 {object} exceptions.Error "Песня после изменения не прошла валидацию, нарушения перечислены в details"
+// @Failure 428 {object} exceptions.Error "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)"
 // @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Deprecated
 // @Router /songs [patch]
 func (cntrl *Controller) UpdateSong(c *gin.Context) {
 	var songPayload models.Song
@@ -205,25 +585,132 @@ func (cntrl *Controller) UpdateSong(c *gin.Context) {
 		return
 	}
 
-	song, err := cntrl.songService.UpdateSong(c.Request.Context(), songPayload.Id, songPayload)
-	if err != nil {
-		logrus.Debugf("update song error: %s", err)
-		exceptions.UpdatingSongError(c)
+	var ok bool
+	songPayload.Lang, ok = parseLang(songPayload.Lang)
+	if !ok {
+		exceptions.InvalidLanguageError(c)
 		return
 	}
 
+	song, err := cntrl.songService.UpdateSong(c.Request.Context(), songPayload.Id, songPayload, parseIfMatch(c))
+	if !handleSongWriteError(c, err) {
+		return
+	}
+
+	c.Header("ETag", songETag(song.Version))
 	c.JSON(http.StatusCreated, song)
 }
 
+// PatchSong godoc
+// @Summary Частичное изменение песни
+// @Description Применяет к редактируемым полям песни (SongDocument) JSON Merge Patch (application/merge-patch+json, RFC 7396,
+// @Description application/json разбирается так же) или JSON Patch (application/json-patch+json, RFC 6902).
+// @Description null в merge patch и remove в JSON Patch очищают поле. release_date в документе - собственная дата песни YYYY-MM-DD,
+// @Description null - дата берется из альбома. Куплеты разбираются заново, только если изменился текст.
+// @Tags songs
+// @Accept  application/merge-patch+json
+// @Accept  application/json-patch+json
+// @Produce  json
+// @Param  id        path    int     true   "ID песни"
+// @Param  patch     body    object  true   "Merge patch - объект с полями SongDocument, JSON Patch - массив операций"
+// @Param  If-Match  header  string  false  "ETag песни из GET /songs/{id}"
+// @Success 200 {object} models.Song      "Песня изменена"
+// @Header  200 {string} ETag             "Новая версия песни"
+// @Failure 400 {object} exceptions.Error "Некорректный патч"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена"
+// @Failure 409 {object} exceptions.Error "Операция test не совпала с песней или у исполнителя уже есть песня с таким названием (ее путь в Location)"
+// @Failure 412 {object} exceptions.Error "Песня изменена, версия не совпадает с If-Match"
+// @Failure 415 {object} exceptions.Error "Неподдерживаемый тип патча"
+// @Failure 422 {object} exceptions.Error "Патч не применяется к песне или песня после патча не прошла валидацию (нарушения в details)"
+// @Failure 428 {object} exceptions.Error "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id} [patch]
+func (cntrl *Controller) PatchSong(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	var format services.SongPatchFormat
+	switch c.ContentType() {
+	case mergePatchContentType, "application/json":
+		format = services.SongMergePatch
+	case jsonPatchContentType:
+		format = services.SongJsonPatch
+	default:
+		exceptions.UnsupportedSongPatchTypeError(c)
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSongDocumentSize))
+	if err != nil {
+		exceptions.InvalidSongPatchError(c)
+		return
+	}
+
+	song, err := cntrl.songService.PatchSong(c.Request.Context(), id, format, patch, parseIfMatch(c))
+	if !handleSongWriteError(c, err) {
+		return
+	}
+
+	c.Header("ETag", songETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
+
+// ReplaceSong godoc
+// @Summary Замена песни
+// @Description Заменяет все редактируемые поля песни. Непереданные поля очищаются, song и group обязательны,
+// @Description release_date - собственная дата песни YYYY-MM-DD, без нее дата берется из альбома.
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param  id        path    int                    true   "ID песни"
+// @Param  song      body    services.SongDocument  true   "Редактируемые поля песни"
+// @Param  If-Match  header  string                 false  "ETag песни из GET /songs/{id}"
+// @Success 200 {object} models.Song      "Песня заменена"
+// @Header  200 {string} ETag             "Новая версия песни"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, тело не является JSON"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена"
+// @Failure 409 {object} exceptions.Error "У исполнителя уже есть песня с таким названием, ее путь в Location"
+// @Failure 412 {object} exceptions.Error "Песня изменена, версия не совпадает с If-Match"
+// @Failure 422 {object} exceptions.Error "Неизвестные поля или поля песни не прошли валидацию (нарушения в details)"
+// @Failure 428 {object} exceptions.Error "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id} [put]
+func (cntrl *Controller) ReplaceSong(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	document, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSongDocumentSize))
+	if err != nil || !json.Valid(document) {
+		exceptions.InvalidPayloadToReplaceSongError(c)
+		return
+	}
+
+	song, err := cntrl.songService.ReplaceSong(c.Request.Context(), id, document, parseIfMatch(c))
+	if !handleSongWriteError(c, err) {
+		return
+	}
+
+	c.Header("ETag", songETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
+
 // DeleteSong godoc
 // @Summary Удаление песни
-// @Description Удаляет песню по ID
+// @Description Переносит песню в корзину. Из корзины песню можно восстановить, пока она не удалена окончательно по истечении TRASH_RETENTION
 // @Tags songs
 // @Accept  json
 // @Produce  json
-// @Param  id  path  int  true  "ID песни"
+// @Param  id        path    int     true   "ID песни"
+// @Param  If-Match  header  string  false  "ETag песни, песня удаляется только в этой версии"
 // @Success 204 {object} any              "Песня успешно удалена"
 // @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена"
+// @Failure 412 {object} exceptions.Error "Песня изменена, версия не совпадает с If-Match"
+// @Failure 428 {object} exceptions.Error "Не передан If-Match, если он обязателен (REQUIRE_IF_MATCH)"
 // @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
 // @Router /songs/{id} [delete]
 func (cntrl *Controller) DeleteSong(c *gin.Context) {
@@ -239,7 +726,15 @@ func (cntrl *Controller) DeleteSong(c *gin.Context) {
 		return
 	}
 
-	err = cntrl.songService.DeleteSong(c.Request.Context(), intId)
+	err = cntrl.songService.DeleteSong(c.Request.Context(), intId, parseIfMatch(c))
+	if errors.Is(err, models.ErrSongNotFound) {
+		exceptions.SongByIdNotFoundError(c)
+		return
+	}
+	if errors.Is(err, models.ErrSongVersionMismatch) {
+		exceptions.SongVersionMismatchError(c)
+		return
+	}
 	if err != nil {
 		logrus.Debugf("delete song error: %s", err)
 		exceptions.DeletingSongError(c)
@@ -248,3 +743,46 @@ func (cntrl *Controller) DeleteSong(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// handleSongWriteError отвечает ошибкой изменения песни и возвращает false, если err не nil
+func handleSongWriteError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case validationFailed(c, err):
+	case songAlreadyExists(c, err):
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+	case errors.Is(err, models.ErrSongVersionMismatch):
+		exceptions.SongVersionMismatchError(c)
+	case errors.Is(err, jsonpatch.ErrInvalidPatch):
+		exceptions.InvalidSongPatchError(c)
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		exceptions.SongPatchTestFailedError(c)
+	case errors.Is(err, jsonpatch.ErrPathNotFound):
+		exceptions.UnprocessableSongPatchError(c)
+	case errors.Is(err, services.ErrInvalidSongDocument):
+		logrus.Debugf("invalid song document: %s", err)
+		exceptions.InvalidSongDocumentError(c)
+	default:
+		logrus.Debugf("update song error: %s", err)
+		exceptions.UpdatingSongError(c)
+	}
+
+	return false
+}
+
+// songAlreadyExists отвечает 409 с Location существующей песни, если err - дубль названия песни у исполнителя
+func songAlreadyExists(c *gin.Context, err error) bool {
+	if !errors.Is(err, models.ErrSongAlreadyExists) {
+		return false
+	}
+
+	var exists *models.SongExistsError
+	if errors.As(err, &exists) {
+		c.Header("Location", songLocation(exists.SongId))
+	}
+
+	exceptions.SongAlreadyExistsError(c)
+	return true
+}
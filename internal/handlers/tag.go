@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+type TagPayload struct {
+	Name string `json:"name"`
+}
+
+type SongTagsPayload struct {
+	Tags []string `json:"tags"`
+}
+
+// GetTagsWithPagination godoc
+// @Summary Получение всех тегов с пагинацией
+// @Description Возвращает теги в алфавитном порядке с количеством песен
+// @Tags tags
+// @Produce  json
+// @Param   page              query     int        false   "Страница, по умолчанию 1" minimum(1)
+// @Param   limit             query     int        false   "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.TagsWithPagination "Успешный ответ"
+// @Failure 400 {object} exceptions.Error            "Неверный запрос, некорректные параметры пагинации"
+// @Failure 500 {object} exceptions.Error            "Внутренняя ошибка сервера"
+// @Router  /tags [get]
+func (cntrl *Controller) GetTagsWithPagination(c *gin.Context) {
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	tags, err := cntrl.tagService.GetAllTagsWithPagination(c.Request.Context(), limit, page)
+	if err != nil {
+		logrus.Debugf("get all tags with pagination error: %s", err)
+		exceptions.FetchingTagsError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// AddTag godoc
+// @Summary Добавление тега
+// @Description Создает новый тег. Теги также создаются автоматически при установке тегов песни
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param tag body TagPayload true "Данные тега"
+// @Success 201 {object} models.Tag       "Тег успешно добавлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 409 {object} exceptions.Error "Тег с таким названием уже существует"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /tags [post]
+func (cntrl *Controller) AddTag(c *gin.Context) {
+	var payload TagPayload
+	err := c.BindJSON(&payload)
+	if err != nil || strings.TrimSpace(payload.Name) == "" {
+		exceptions.InvalidPayloadToSaveATagError(c)
+		return
+	}
+
+	tag := models.Tag{
+		Name: payload.Name,
+	}
+
+	err = cntrl.tagService.AddTag(c.Request.Context(), &tag)
+	if errors.Is(err, models.ErrTagAlreadyExists) {
+		exceptions.TagAlreadyExistsError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("add tag error: %s", err)
+		exceptions.CreatingTagError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// DeleteTag godoc
+// @Summary Удаление тега
+// @Description Удаляет тег по ID и снимает его со всех песен
+// @Tags tags
+// @Produce  json
+// @Param  id  path  int  true  "ID тега"
+// @Success 204 {object} any              "Тег успешно удален"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Тег с предоставленным ID не найден"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /tags/{id} [delete]
+func (cntrl *Controller) DeleteTag(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.TagIdIsNotProvidedError, exceptions.FailedToParseTagIdError)
+	if !ok {
+		return
+	}
+
+	err := cntrl.tagService.DeleteTag(c.Request.Context(), id)
+	if errors.Is(err, models.ErrTagNotFound) {
+		exceptions.TagByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("delete tag error: %s", err)
+		exceptions.DeletingTagError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetSongTags godoc
+// @Summary Установка тегов песни
+// @Description Заменяет теги песни переданным списком, несуществующие теги создаются
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param  id    path  int              true  "ID песни"
+// @Param  tags  body  SongTagsPayload  true  "Теги песни, пустой список снимает все теги"
+// @Success 200 {object} models.Song      "Теги песни обновлены"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/tags [put]
+func (cntrl *Controller) SetSongTags(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	var payload SongTagsPayload
+	err := c.BindJSON(&payload)
+	if err != nil || len(nonEmpty(payload.Tags)) != len(payload.Tags) {
+		exceptions.InvalidPayloadToSaveSongClassificationError(c)
+		return
+	}
+
+	err = cntrl.tagService.SetSongTags(c.Request.Context(), id, payload.Tags)
+	if errors.Is(err, models.ErrSongNotFound) {
+		exceptions.SongByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("set song tags error: %s", err)
+		exceptions.UpdatingSongError(c)
+		return
+	}
+
+	song, err := cntrl.songService.GetSongById(c.Request.Context(), id)
+	if err != nil {
+		logrus.Debugf("get song by id error: %s", err)
+		exceptions.SongByIdNotFoundError(c)
+		return
+	}
+
+	c.Header("ETag", songETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/sirupsen/logrus"
+)
+
+// GetTrashWithPagination godoc
+// @Summary Получение корзины песен
+// @Description Возвращает удаленные песни от последней удаленной, deleted_at - время удаления.
+// @Description Песни хранятся в корзине TRASH_RETENTION, затем удаляются окончательно
+// @Tags songs
+// @Produce  json
+// @Param  page   query  int  false  "Страница, по умолчанию 1" minimum(1)
+// @Param  limit  query  int  false  "Количество элементов, по умолчанию 10, больше 100 уменьшается до 100" minimum(1) maximum(100)
+// @Success 200 {object} services.TrashedSongsWithPagination "Успешный ответ"
+// @Failure 400 {object} exceptions.Error                    "Неверный запрос, некорректные параметры пагинации"
+// @Failure 500 {object} exceptions.Error                    "Внутренняя ошибка сервера"
+// @Router /songs/trash [get]
+func (cntrl *Controller) GetTrashWithPagination(c *gin.Context) {
+	limit, page, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	trash, err := cntrl.trashService.GetTrash(c.Request.Context(), limit, page)
+	if err != nil {
+		logrus.Debugf("get trash error: %s", err)
+		exceptions.FetchingTrashError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// RestoreSongFromTrash godoc
+// @Summary Восстановление песни из корзины
+// @Description Возвращает удаленную песню из корзины и добавляет в ее историю ревизию restore
+// @Tags songs
+// @Produce  json
+// @Param  id  path  int  true  "ID песни"
+// @Success 200 {object} models.Song      "Песня восстановлена"
+// @Failure 400 {object} exceptions.Error "Неверный запрос, ID песни не предоставлен или некорректен"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена в корзине"
+// @Failure 409 {object} exceptions.Error "У исполнителя уже есть песня с таким названием, ее путь в Location"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/restore [post]
+func (cntrl *Controller) RestoreSongFromTrash(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	song, err := cntrl.trashService.RestoreSong(c.Request.Context(), id)
+	if errors.Is(err, models.ErrSongNotInTrash) {
+		exceptions.SongNotInTrashError(c)
+		return
+	}
+	if songAlreadyExists(c, err) {
+		return
+	}
+	if err != nil {
+		logrus.Debugf("restore song from trash error: %s", err)
+		exceptions.RestoringSongFromTrashError(c)
+		return
+	}
+
+	c.Header("ETag", songETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
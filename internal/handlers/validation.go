@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/shlmvgleb/em-task/pkg/validation"
+)
+
+// validationFailed отвечает 422 со списком нарушений по полям, если err - ошибка валидации
+func validationFailed(c *gin.Context, err error) bool {
+	var invalid validation.Errors
+	if !errors.As(err, &invalid) {
+		return false
+	}
+
+	details := make([]exceptions.ErrorDetail, 0, len(invalid))
+	for _, fe := range invalid {
+		details = append(details, exceptions.ErrorDetail{
+			Field:   fe.Field,
+			Code:    fe.Code,
+			Message: fe.Message,
+		})
+	}
+
+	exceptions.ValidationFailedError(c, details)
+	return true
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/exceptions"
+	"github.com/shlmvgleb/em-task/pkg/lyrics"
+	"github.com/sirupsen/logrus"
+)
+
+type VersePayload struct {
+	Kind models.VerseKind `json:"kind"`
+	Text string           `json:"text"`
+	// позиция для вставки, 0 - в конец; при обновлении куплета не используется
+	Position int `json:"position"`
+}
+
+type VerseMovePayload struct {
+	Position int `json:"position"`
+}
+
+// GetSongVerses godoc
+// @Summary Получение куплетов песни
+// @Description Возвращает куплеты песни по порядку
+// @Tags songs
+// @Produce  json
+// @Param  id  path  int  true  "ID песни"
+// @Success 200 {object} []models.Verse   "Успешный ответ"
+// @Failure 400 {object} exceptions.Error "Неверный запрос, ID песни не предоставлен или некорректен"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/verses [get]
+func (cntrl *Controller) GetSongVerses(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	verses, err := cntrl.verseService.GetSongVerses(c.Request.Context(), id)
+	if errors.Is(err, models.ErrSongNotFound) {
+		exceptions.SongByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("get song verses error: %s", err)
+		exceptions.FetchingVersesError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, verses)
+}
+
+// AddVerse godoc
+// @Summary Добавление куплета
+// @Description Вставляет куплет на позицию position, последующие куплеты сдвигаются. Текст песни пересобирается из куплетов
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param  id     path  int           true  "ID песни"
+// @Param  verse  body  VersePayload  true  "Куплет"
+// @Success 201 {object} models.Verse     "Куплет добавлен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Песня с предоставленным ID не найдена"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/verses [post]
+func (cntrl *Controller) AddVerse(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	verse, ok := parseVersePayload(c)
+	if !ok {
+		exceptions.InvalidPayloadToSaveAVerseError(c)
+		return
+	}
+
+	err := cntrl.verseService.AddVerse(c.Request.Context(), id, verse)
+	if errors.Is(err, models.ErrSongNotFound) {
+		exceptions.SongByIdNotFoundError(c)
+		return
+	}
+	if err != nil {
+		logrus.Debugf("add verse error: %s", err)
+		exceptions.CreatingVerseError(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, verse)
+}
+
+// UpdateVerse godoc
+// @Summary Изменение куплета
+// @Description Заменяет тип и текст куплета, позиция не меняется. Текст песни пересобирается из куплетов
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param  id        path  int           true  "ID песни"
+// @Param  verse_id  path  int           true  "ID куплета"
+// @Param  verse     body  VersePayload  true  "Куплет"
+// @Success 200 {object} models.Verse     "Куплет изменен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Песня или куплет не найдены"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/verses/{verse_id} [put]
+func (cntrl *Controller) UpdateVerse(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	verseId, ok := parseIdParam(c, "verse_id", exceptions.VerseIdIsNotProvidedError, exceptions.FailedToParseVerseIdError)
+	if !ok {
+		return
+	}
+
+	verse, ok := parseVersePayload(c)
+	if !ok {
+		exceptions.InvalidPayloadToSaveAVerseError(c)
+		return
+	}
+
+	updated, err := cntrl.verseService.UpdateVerse(c.Request.Context(), id, verseId, verse)
+	switch {
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrVerseNotFound):
+		exceptions.VerseByIdNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("update verse error: %s", err)
+		exceptions.UpdatingVerseError(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// MoveVerse godoc
+// @Summary Перемещение куплета
+// @Description Переносит куплет на позицию position, остальные куплеты сдвигаются. Позиция за пределами песни приводится к первой или последней
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param  id        path  int               true  "ID песни"
+// @Param  verse_id  path  int               true  "ID куплета"
+// @Param  verse     body  VerseMovePayload  true  "Новая позиция"
+// @Success 204 {object} any              "Куплет перемещен"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Песня или куплет не найдены"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/verses/{verse_id} [patch]
+func (cntrl *Controller) MoveVerse(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	verseId, ok := parseIdParam(c, "verse_id", exceptions.VerseIdIsNotProvidedError, exceptions.FailedToParseVerseIdError)
+	if !ok {
+		return
+	}
+
+	var payload VerseMovePayload
+	err := c.BindJSON(&payload)
+	if err != nil || payload.Position < 1 {
+		exceptions.InvalidPayloadToSaveAVerseError(c)
+		return
+	}
+
+	err = cntrl.verseService.MoveVerse(c.Request.Context(), id, verseId, payload.Position)
+	switch {
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrVerseNotFound):
+		exceptions.VerseByIdNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("move verse error: %s", err)
+		exceptions.UpdatingVerseError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteVerse godoc
+// @Summary Удаление куплета
+// @Description Удаляет куплет, последующие куплеты сдвигаются. Текст песни пересобирается из куплетов
+// @Tags songs
+// @Produce  json
+// @Param  id        path  int  true  "ID песни"
+// @Param  verse_id  path  int  true  "ID куплета"
+// @Success 204 {object} any              "Куплет удален"
+// @Failure 400 {object} exceptions.Error "Некорректный запрос, неправильный формат данных"
+// @Failure 404 {object} exceptions.Error "Песня или куплет не найдены"
+// @Failure 500 {object} exceptions.Error "Внутренняя ошибка сервера"
+// @Router /songs/{id}/verses/{verse_id} [delete]
+func (cntrl *Controller) DeleteVerse(c *gin.Context) {
+	id, ok := parseIdParam(c, "id", exceptions.SongIdIsNotProvidedError, exceptions.FailedToParseSongIdError)
+	if !ok {
+		return
+	}
+
+	verseId, ok := parseIdParam(c, "verse_id", exceptions.VerseIdIsNotProvidedError, exceptions.FailedToParseVerseIdError)
+	if !ok {
+		return
+	}
+
+	err := cntrl.verseService.DeleteVerse(c.Request.Context(), id, verseId)
+	switch {
+	case errors.Is(err, models.ErrSongNotFound):
+		exceptions.SongByIdNotFoundError(c)
+		return
+	case errors.Is(err, models.ErrVerseNotFound):
+		exceptions.VerseByIdNotFoundError(c)
+		return
+	case err != nil:
+		logrus.Debugf("delete verse error: %s", err)
+		exceptions.DeletingVerseError(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseVersePayload читает куплет из тела запроса. Текст нормализуется так же, как при разборе текста песни,
+// и должен остаться одним куплетом, иначе границы куплетов в тексте песни разойдутся с таблицей куплетов
+func parseVersePayload(c *gin.Context) (*models.Verse, bool) {
+	var payload VersePayload
+	err := c.BindJSON(&payload)
+	if err != nil || payload.Position < 0 {
+		return nil, false
+	}
+
+	if payload.Kind == "" {
+		payload.Kind = models.VerseKindVerse
+	}
+
+	text := lyrics.Normalize(payload.Text)
+	if !slices.Contains(models.VerseKinds, payload.Kind) || !lyrics.IsVerse(text) {
+		return nil, false
+	}
+
+	return &models.Verse{
+		Position: payload.Position,
+		Kind:     payload.Kind,
+		Text:     text,
+	}, true
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrAlbumNotFound      = errors.New("album is not found")
+	ErrAlbumTrackNotFound = errors.New("album track is not found")
+	ErrAlbumTrackTaken    = errors.New("album track position is already taken")
+)
+
+type AlbumRepository interface {
+	GetWithPagination(ctx context.Context, limit int, offset int) ([]*Album, int, error)
+	// GetById возвращает альбом вместе с треками, упорядоченными по номеру диска и трека
+	GetById(ctx context.Context, id int64) (*Album, error)
+	Add(ctx context.Context, album *Album) error
+	Update(ctx context.Context, id int64, album *Album) (*Album, error)
+	Delete(ctx context.Context, id int64) error
+	SetTrack(ctx context.Context, albumId int64, songId int64, discNumber int, trackNumber int) error
+	RemoveTrack(ctx context.Context, albumId int64, songId int64) error
+}
+
+// Album представляет релиз исполнителя
+// @Description Альбом исполнителя с треклистом, упорядоченным по номеру диска и трека.
+// @Tags albums
+type Album struct {
+	Id          int64      `json:"id,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	ArtistId    int64      `json:"artist_id,omitempty"`
+	Artist      string     `json:"artist,omitempty"`
+	ReleaseDate *time.Time `json:"release_date,omitempty"`
+	CoverLink   string     `json:"cover_link,omitempty"`
+	Tracks      []*Song    `json:"tracks,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrArtistNotFound      = errors.New("artist is not found")
+	ErrArtistAlreadyExists = errors.New("artist with the same name already exists")
+	ErrArtistHasSongs      = errors.New("artist has songs")
+)
+
+type ArtistRepository interface {
+	GetWithPagination(ctx context.Context, limit int, offset int) ([]*Artist, int, error)
+	GetById(ctx context.Context, id int64) (*Artist, error)
+	Add(ctx context.Context, artist *Artist) error
+	Update(ctx context.Context, id int64, artist *Artist) (*Artist, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// Artist представляет исполнителя (группу)
+// @Description Исполнитель песен. Имена сравниваются без учета регистра и лишних пробелов.
+// @Tags artists
+type Artist struct {
+	Id   int64  `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
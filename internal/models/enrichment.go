@@ -0,0 +1,62 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrEnrichmentSuperseded - песня изменилась, пока задача обогащения выполнялась, детали не записаны
+	ErrEnrichmentSuperseded = errors.New("song was changed during enrichment")
+	// ErrEnrichmentJobLost - задачу после LockTimeout забрал другой воркер, результат этого воркера не записан
+	ErrEnrichmentJobLost = errors.New("enrichment job was claimed by another worker")
+)
+
+type EnrichmentStatus string
+
+const (
+	EnrichmentPending   EnrichmentStatus = "pending"
+	EnrichmentCompleted EnrichmentStatus = "completed"
+	EnrichmentFailed    EnrichmentStatus = "failed"
+)
+
+type EnrichmentJobStatus string
+
+const (
+	EnrichmentJobQueued     EnrichmentJobStatus = "queued"
+	EnrichmentJobProcessing EnrichmentJobStatus = "processing"
+	EnrichmentJobDone       EnrichmentJobStatus = "done"
+	// задача исчерпала попытки или получила неисправимую ошибку (dead letter)
+	EnrichmentJobDead EnrichmentJobStatus = "dead"
+)
+
+type EnrichmentJobRepository interface {
+	// Claim забирает готовую к выполнению задачу (или зависшую дольше lockTimeout), nil - если задач нет
+	Claim(ctx context.Context, lockTimeout time.Duration) (*EnrichmentJob, error)
+	// Complete записывает детали песни, только если песня все еще ждет обогащения и ее версия равна song.Version.
+	// Песня с другой версией - ErrEnrichmentSuperseded, песня, которая уже не ждет обогащения, - задача завершается
+	// без записи деталей.
+	// Complete, Retry и Bury меняют задачу, только пока она занята этим воркером (job.LockedAt),
+	// иначе возвращают ErrEnrichmentJobLost
+	Complete(ctx context.Context, job *EnrichmentJob, song *Song) error
+	Retry(ctx context.Context, job *EnrichmentJob, runAt time.Time, jobErr string) error
+	Bury(ctx context.Context, job *EnrichmentJob, jobErr string) error
+	// GetLatestBySongId возвращает последнюю задачу песни, nil - если задач не было
+	GetLatestBySongId(ctx context.Context, songId int64) (*EnrichmentJob, error)
+}
+
+// EnrichmentJob представляет задачу на заполнение деталей песни из внешнего API
+// @Description Задача обогащения песни: статус, количество попыток, последняя ошибка и время следующего запуска.
+type EnrichmentJob struct {
+	Id        int64               `json:"id"`
+	SongId    int64               `json:"song_id"`
+	Status    EnrichmentJobStatus `json:"status"`
+	Attempts  int                 `json:"attempts"`
+	LastError string              `json:"last_error,omitempty"`
+	RunAt     time.Time           `json:"run_at"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	// время, когда задачу забрал воркер, по нему воркер проверяет, что задача все еще за ним
+	LockedAt *time.Time `json:"-"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrGenreNotFound       = errors.New("genre is not found")
+	ErrGenreAlreadyExists  = errors.New("genre with the same name already exists")
+	ErrGenreHasSubgenres   = errors.New("genre has subgenres")
+	ErrGenreParentNotFound = errors.New("parent genre is not found")
+	ErrGenreCycle          = errors.New("genre can not be a subgenre of itself")
+)
+
+type GenreRepository interface {
+	GetAll(ctx context.Context) ([]*Genre, error)
+	GetById(ctx context.Context, id int64) (*Genre, error)
+	Add(ctx context.Context, genre *Genre) error
+	Update(ctx context.Context, id int64, genre *Genre) (*Genre, error)
+	Delete(ctx context.Context, id int64) error
+	// SetSongGenres заменяет жанры песни переданными, жанры ищутся по имени
+	SetSongGenres(ctx context.Context, songId int64, names []string) error
+}
+
+// Genre представляет музыкальный жанр
+// @Description Жанр песни. Жанры образуют иерархию, например rock > alternative rock.
+// @Tags genres
+type Genre struct {
+	Id       int64    `json:"id,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	ParentId *int64   `json:"parent_id,omitempty"`
+	Children []*Genre `json:"children,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key is already used with another request")
+	ErrIdempotencyKeyInProgress = errors.New("request with the idempotency key is in progress")
+)
+
+// IdempotentResponse - сохраненный ответ на запрос с ключом идемпотентности
+type IdempotentResponse struct {
+	Status  int
+	Headers map[string]string
+	Body    []byte
+}
+
+type IdempotencyRepository interface {
+	// Reserve занимает ключ за запросом с отпечатком fingerprint. Если ключ уже занят тем же запросом и ответ
+	// сохранен, возвращает ответ. Ключи, созданные раньше expiredBefore, и незавершенные запросы,
+	// начатые раньше staleBefore, занимаются заново
+	Reserve(ctx context.Context, key string, fingerprint string, expiredBefore time.Time, staleBefore time.Time) (*IdempotentResponse, error)
+	// Complete сохраняет ответ на запрос, занявший ключ
+	Complete(ctx context.Context, key string, response *IdempotentResponse) error
+	// Release освобождает ключ запроса, завершившегося без ответа, который можно повторить
+	Release(ctx context.Context, key string) error
+	// Purge удаляет ключи, созданные раньше before, и возвращает их количество
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSyncedLyricsNotFound  = errors.New("synced lyrics are not found")
+	ErrLyricsVariantNotFound = errors.New("lyrics variant is not found")
+)
+
+type LyricsVariantKind string
+
+const (
+	LyricsVariantTranslation     LyricsVariantKind = "translation"
+	LyricsVariantTransliteration LyricsVariantKind = "transliteration"
+)
+
+var LyricsVariantKinds = []LyricsVariantKind{
+	LyricsVariantTranslation,
+	LyricsVariantTransliteration,
+}
+
+type LyricsRepository interface {
+	// GetLines возвращает строки синхронизированного текста по порядку, пустой список - текст не синхронизирован
+	GetLines(ctx context.Context, songId int64) ([]*LyricsLine, error)
+	// ReplaceLines заменяет строки песни, а вместе с ними куплеты и текст песни, собранные из строк
+	ReplaceLines(ctx context.Context, songId int64, lines []*LyricsLine, verses []*Verse) error
+	// GetVariants возвращает варианты текста песни на других языках, упорядоченные по языку
+	GetVariants(ctx context.Context, songId int64) ([]*LyricsVariant, error)
+	// SaveVariant добавляет вариант или заменяет вариант на том же языке, created - вариант добавлен
+	SaveVariant(ctx context.Context, songId int64, variant *LyricsVariant) (created bool, err error)
+	DeleteVariant(ctx context.Context, songId int64, lang string) error
+}
+
+// LyricsLine представляет строку синхронизированного текста песни
+// @Description Строка текста с временем начала в миллисекундах. Пустой текст - пауза между куплетами.
+// @Tags songs
+type LyricsLine struct {
+	Position int    `json:"position"`
+	TimeMs   int64  `json:"time_ms"`
+	Text     string `json:"text"`
+}
+
+// LyricsVariant представляет перевод или транслитерацию текста песни
+// @Description Вариант текста песни на другом языке. Язык - тег BCP 47, например en или ru-Latn.
+// @Tags songs
+type LyricsVariant struct {
+	Lang      string            `json:"lang"`
+	Kind      LyricsVariantKind `json:"kind"`
+	Text      string            `json:"text"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrPlaylistNotFound      = errors.New("playlist is not found")
+	ErrPlaylistEntryNotFound = errors.New("playlist entry is not found")
+	ErrPlaylistSongNotFound  = errors.New("song to add to playlist is not found")
+)
+
+type PlaylistRepository interface {
+	GetWithPagination(ctx context.Context, limit int, offset int) ([]*Playlist, int, error)
+	GetById(ctx context.Context, id int64) (*Playlist, error)
+	GetEntriesWithPagination(ctx context.Context, playlistId int64, limit int, offset int) ([]*PlaylistEntry, int, error)
+	Add(ctx context.Context, playlist *Playlist) error
+	Rename(ctx context.Context, id int64, name string) (*Playlist, error)
+	Delete(ctx context.Context, id int64) error
+	// InsertEntry вставляет песню на позицию position, сдвигая последующие записи; position 0 - в конец.
+	// Позиции в InsertEntry и MoveEntry, как и в GetEntriesWithPagination, считаются только среди записей с песнями не из корзины
+	InsertEntry(ctx context.Context, playlistId int64, songId int64, position int) (*PlaylistEntry, error)
+	MoveEntry(ctx context.Context, playlistId int64, entryId int64, position int) error
+	RemoveEntry(ctx context.Context, playlistId int64, entryId int64) error
+}
+
+// Playlist представляет пользовательский плейлист
+// @Description Плейлист с упорядоченным списком песен.
+// @Tags playlists
+type Playlist struct {
+	Id            int64     `json:"id,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	EntriesAmount int       `json:"entries_amount"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// PlaylistEntry представляет песню на определенной позиции плейлиста
+// @Description Запись плейлиста. Одна и та же песня может встречаться в плейлисте несколько раз.
+// @Description Позиция считается среди видимых записей: записи с песнями из корзины скрыты и не занимают позиций.
+// @Tags playlists
+type PlaylistEntry struct {
+	Id       int64     `json:"id"`
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"added_at"`
+	Song     *Song     `json:"song,omitempty"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrSongRevisionNotFound = errors.New("song revision is not found")
+
+type SongRevisionAction string
+
+const (
+	RevisionCreate  SongRevisionAction = "create"
+	RevisionUpdate  SongRevisionAction = "update"
+	RevisionDelete  SongRevisionAction = "delete"
+	RevisionRestore SongRevisionAction = "restore"
+)
+
+type SongRevisionRepository interface {
+	// GetBySongId возвращает ревизии песни от последней к первой без снимков; история удаленной песни тоже доступна
+	GetBySongId(ctx context.Context, songId int64, limit int, offset int) ([]*SongRevision, int, error)
+	// GetByRevision возвращает ревизию со снимком
+	GetByRevision(ctx context.Context, songId int64, revision int) (*SongRevision, error)
+	// Restore записывает в песню поля снимка ревизии и добавляет ревизию restore.
+	// ifMatch - допустимые версии песни, nil - любая
+	Restore(ctx context.Context, songId int64, revision int, song *Song, ifMatch []int) (*Song, error)
+}
+
+// SongSnapshot - редактируемые поля песни на момент ревизии
+type SongSnapshot struct {
+	Song  string `json:"song"`
+	Group string `json:"group"`
+	Text  string `json:"text"`
+	Link  string `json:"link"`
+	// дата в формате 2006-01-02, null - дата не указана
+	ReleaseDate *string `json:"release_date"`
+	Lang        *string `json:"lang"`
+}
+
+// SongRevision представляет правку песни
+// @Description Ревизия песни: снимок редактируемых полей после правки, изменившиеся поля и автор правки.
+// @Tags songs
+type SongRevision struct {
+	Revision int                `json:"revision"`
+	SongId   int64              `json:"song_id"`
+	Action   SongRevisionAction `json:"action"`
+	// значение заголовка X-Actor запроса, сделавшего правку
+	Actor   string   `json:"actor,omitempty"`
+	Changed []string `json:"changed"`
+	// номер ревизии, из которой восстановлена песня; у восстановления из корзины не заполняется
+	RestoredFrom *int          `json:"restored_from,omitempty"`
+	Snapshot     *SongSnapshot `json:"snapshot,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type actorKey struct{}
+
+// WithActor сохраняет в контексте автора правок, он записывается в ревизии песен
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает автора правок из контекста, пустая строка - автор неизвестен
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
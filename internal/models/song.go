@@ -2,25 +2,278 @@ package models
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+var (
+	ErrSongNotFound        = errors.New("song is not found")
+	ErrSongAlreadyExists   = errors.New("song with the same artist and title already exists")
+	ErrSongNotInTrash      = errors.New("song is not in trash")
+	ErrSongVersionMismatch = errors.New("song version does not match")
+	ErrInvalidSongSort     = errors.New("invalid song sort")
+	ErrInvalidSongCursor   = errors.New("invalid song cursor")
+)
+
+// SongExistsError - ErrSongAlreadyExists с id песни, которая уже есть у исполнителя
+type SongExistsError struct {
+	SongId int64
+}
+
+func (e *SongExistsError) Error() string {
+	return fmt.Sprintf("%s: song %d", ErrSongAlreadyExists, e.SongId)
+}
+
+func (e *SongExistsError) Unwrap() error {
+	return ErrSongAlreadyExists
+}
+
+// ClassificationMatch определяет, должна ли песня иметь хотя бы одно из значений фильтра или все сразу
+type ClassificationMatch string
+
+const (
+	MatchAny ClassificationMatch = "any"
+	MatchAll ClassificationMatch = "all"
+)
+
+// TextMatch определяет способ сравнения строкового фильтра: целиком или по началу строки.
+// Сравнение не учитывает регистр и лишние пробелы.
+type TextMatch string
+
+const (
+	MatchExact  TextMatch = "exact"
+	MatchPrefix TextMatch = "prefix"
+)
+
+// SongFilter описывает условия выборки песен, пустые поля не ограничивают выборку.
+// Фильтр по жанру включает все его поджанры.
+type SongFilter struct {
+	SearchQuery string
+	// нечеткий поиск по названию и исполнителю, устойчивый к опечаткам
+	Fuzzy string
+
+	Group      string
+	GroupMatch TextMatch
+	Song       string
+	SongMatch  TextMatch
+
+	// границы включительно, сравниваются с датой выпуска с учетом даты альбома
+	ReleaseDateFrom *time.Time
+	ReleaseDateTo   *time.Time
+	HasLink         *bool
+	CreatedAfter    *time.Time
+	UpdatedAfter    *time.Time
+
+	Genres     []string
+	GenreMatch ClassificationMatch
+	Tags       []string
+	TagMatch   ClassificationMatch
+
+	// язык полнотекстового поиска: запрос разбирается конфигурацией этого языка и ищется в вариантах текста
+	// на этом языке и в оригиналах, проиндексированных той же конфигурацией
+	Lang string
+}
+
+type SongSortField string
+
+const (
+	SortByReleaseDate SongSortField = "release_date"
+	SortByCreatedAt   SongSortField = "created_at"
+	SortByUpdatedAt   SongSortField = "updated_at"
+	SortBySong        SongSortField = "song"
+	SortByGroup       SongSortField = "group"
+	// релевантность полнотекстового поиска, доступна только вместе с SearchQuery
+	SortByRelevance SongSortField = "relevance"
+	// сходство с нечетким запросом, доступно только вместе с Fuzzy
+	SortBySimilarity SongSortField = "similarity"
+)
+
+var SongSortFields = []SongSortField{
+	SortByReleaseDate,
+	SortByCreatedAt,
+	SortByUpdatedAt,
+	SortBySong,
+	SortByGroup,
+	SortByRelevance,
+	SortBySimilarity,
+}
+
+// SongSortKey - один ключ сортировки списка песен. Песни с одинаковыми ключами упорядочиваются по id.
+type SongSortKey struct {
+	Field SongSortField
+	Desc  bool
+}
+
+// EffectiveSongSort возвращает сортировку, с которой выбираются песни: пустая сортировка заменяется
+// сортировкой по умолчанию - по сходству для нечеткого поиска, иначе по дате создания
+func EffectiveSongSort(sort []SongSortKey, filter SongFilter) []SongSortKey {
+	if len(sort) > 0 {
+		return sort
+	}
+
+	if filter.Fuzzy != "" {
+		return []SongSortKey{{Field: SortBySimilarity, Desc: true}}
+	}
+
+	return []SongSortKey{{Field: SortByCreatedAt}}
+}
+
+// SongCount определяет, как считать количество найденных песен
+type SongCount string
+
+const (
+	CountExact SongCount = "exact"
+	// оценка планировщика postgres, не требует обхода всех найденных песен
+	CountEstimate SongCount = "estimate"
+	CountNone     SongCount = "none"
+)
+
+// SongKeyset - значения выражений сортировки граничной песни для keyset-пагинации
+type SongKeyset struct {
+	Values []string
+	// true - выбрать песни, стоящие перед граничной
+	Backward bool
+}
+
+// SongPage - страница песен, выбранная по SongKeyset
+type SongPage struct {
+	Songs []*Song
+	// значения выражений сортировки первой и последней песни страницы
+	First []string
+	Last  []string
+	// за страницей в направлении выборки есть еще песни
+	HasMore bool
+	// количество найденных песен, nil при CountNone
+	Total *int
+}
+
+// FacetCount - количество найденных песен с определенным жанром или тегом
+type FacetCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type SongFacets struct {
+	Genres []FacetCount `json:"genres"`
+	Tags   []FacetCount `json:"tags"`
+}
+
 type SongRepository interface {
-	GetWithSearchAndPagination(ctx context.Context, searchQuery string, limit int, offset int) ([]*Song, int, error)
+	GetWithFilterAndPagination(
+		ctx context.Context,
+		filter SongFilter,
+		sort []SongSortKey,
+		count SongCount,
+		limit int, offset int,
+	) ([]*Song, *int, error)
+	GetWithFilterAndKeyset(
+		ctx context.Context,
+		filter SongFilter,
+		sort []SongSortKey,
+		keyset *SongKeyset,
+		count SongCount,
+		limit int,
+	) (*SongPage, error)
+	GetFacets(ctx context.Context, filter SongFilter) (*SongFacets, error)
+	GetSuggestions(ctx context.Context, input string, limit int) ([]*SongSuggestion, error)
+	SearchLyrics(ctx context.Context, query LyricsQuery, limit int, offset int) ([]*LyricsMatch, int, error)
+	GetByArtistIdWithPagination(ctx context.Context, artistId int64, limit int, offset int) ([]*Song, int, error)
 	GetById(ctx context.Context, id int64) (*Song, error)
 	Add(ctx context.Context, song *Song) error
-	Update(ctx context.Context, id int64, song *Song) (*Song, error)
-	Delete(ctx context.Context, id int64) error
+	// edit правит сохраненную песню перед записью; ifMatch - допустимые версии песни, nil - без проверки версии
+	Update(ctx context.Context, id int64, edit func(song *Song) error, ifMatch []int) (*Song, error)
+	Delete(ctx context.Context, id int64, ifMatch []int) error
+}
+
+// SongTrashRepository - корзина удаленных песен
+type SongTrashRepository interface {
+	GetTrashWithPagination(ctx context.Context, limit int, offset int) ([]*Song, int, error)
+	RestoreFromTrash(ctx context.Context, id int64) (*Song, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
 }
 
 // Song представляет информацию о песне
 // @Description Структура, содержащая данные о песне, такие как группа, название песни, текст, дата выпуска и ссылка.
 // @Tags songs
 type Song struct {
-	Id          int64     `json:"id,omitempty"`
-	Group       string    `json:"group,omitempty"`
-	Song        string    `json:"song,omitempty"`
-	Text        string    `json:"text,omitempty"`
-	ReleaseDate time.Time `json:"release_date,omitempty"`
-	Link        string    `json:"link,omitempty"`
+	Id               int64            `json:"id,omitempty"`
+	Group            string           `json:"group,omitempty"`
+	ArtistId         int64            `json:"artist_id,omitempty"`
+	Song             string           `json:"song,omitempty"`
+	Text             string           `json:"text,omitempty"`
+	ReleaseDate      *time.Time       `json:"release_date,omitempty"`
+	Link             string           `json:"link,omitempty"`
+	EnrichmentStatus EnrichmentStatus `json:"enrichment_status,omitempty"`
+	AlbumId          *int64           `json:"album_id,omitempty"`
+	DiscNumber       *int             `json:"disc_number,omitempty"`
+	TrackNumber      *int             `json:"track_number,omitempty"`
+	Genres           []string         `json:"genres,omitempty"`
+	Tags             []string         `json:"tags,omitempty"`
+	// язык оригинального текста, тег BCP 47
+	Lang string `json:"lang,omitempty"`
+	// true, если у песни нет своей даты выпуска и ReleaseDate взята из альбома
+	ReleaseDateInherited bool `json:"release_date_inherited,omitempty"`
+	// совпадения с поисковым запросом, заполняется только в результатах поиска
+	Highlight *SongHighlight `json:"highlight,omitempty"`
+	// совпадение с нечетким запросом, заполняется только в результатах нечеткого поиска
+	Match *SongMatch `json:"match,omitempty"`
+	// язык варианта текста, выбранного вместо оригинала по Accept-Language или lang
+	LyricsLang string `json:"lyrics_lang,omitempty"`
+	// версия песни, увеличивается при каждой правке; отдается также в заголовке ETag
+	Version int `json:"version,omitempty"`
+	// время удаления в корзину, заполняется только в списке корзины
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// куплеты, на которые разобран Text при записи; nil - куплеты песни не меняются
+	Verses []*Verse `json:"-"`
+}
+
+type SongMatchField string
+
+const (
+	MatchFieldSong  SongMatchField = "song"
+	MatchFieldGroup SongMatchField = "group"
+)
+
+// SongMatch - поле песни, лучше всего совпавшее с нечетким запросом, и сходство с запросом от 0 до 1
+type SongMatch struct {
+	Field SongMatchField `json:"field"`
+	Score float64        `json:"score"`
+}
+
+// SongSuggestion - подсказка для поиска по мере ввода
+type SongSuggestion struct {
+	Id    int64     `json:"id"`
+	Song  string    `json:"song"`
+	Group string    `json:"group"`
+	Match SongMatch `json:"match"`
+}
+
+// LyricsQuery - запрос поиска по текстам песен. Без Distance Text разбирается как запрос веб-поиска,
+// фраза в кавычках ищется целиком.
+type LyricsQuery struct {
+	Text string
+	// если больше 0, все слова Text должны встретиться в куплете не дальше Distance слов друг от друга
+	Distance int
+	// язык поиска, см. SongFilter.Lang
+	Lang string
+}
+
+// LyricsMatch - песня, в тексте которой найден запрос, и номера куплетов с совпадениями.
+// Номера - позиции куплетов, они совпадают со страницами пагинации песни по куплетам.
+type LyricsMatch struct {
+	Id     int64  `json:"id"`
+	Song   string `json:"song"`
+	Group  string `json:"group"`
+	Verses []int  `json:"verses"`
+	// язык варианта текста, в котором найдены куплеты; пусто - оригинал
+	Lang string `json:"lang,omitempty"`
+}
+
+// SongHighlight - поля песни, в которых совпадения с поисковым запросом обернуты в <mark></mark>
+type SongHighlight struct {
+	Song  string `json:"song"`
+	Group string `json:"group"`
+	// фрагменты текста песни вокруг совпадений, разделенные " ... "
+	Text string `json:"text"`
 }
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrTagNotFound      = errors.New("tag is not found")
+	ErrTagAlreadyExists = errors.New("tag with the same name already exists")
+)
+
+type TagRepository interface {
+	GetWithPagination(ctx context.Context, limit int, offset int) ([]*Tag, int, error)
+	Add(ctx context.Context, tag *Tag) error
+	Delete(ctx context.Context, id int64) error
+	// SetSongTags заменяет теги песни переданными, отсутствующие теги создаются
+	SetSongTags(ctx context.Context, songId int64, names []string) error
+}
+
+// Tag представляет произвольную метку песни
+// @Description Свободная метка песни, например "summer" или "live".
+// @Tags tags
+type Tag struct {
+	Id          int64  `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	SongsAmount int    `json:"songs_amount"`
+}
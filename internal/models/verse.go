@@ -0,0 +1,43 @@
+package models
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrVerseNotFound = errors.New("verse is not found")
+
+type VerseKind string
+
+const (
+	VerseKindVerse  VerseKind = "verse"
+	VerseKindChorus VerseKind = "chorus"
+	VerseKindBridge VerseKind = "bridge"
+)
+
+var VerseKinds = []VerseKind{
+	VerseKindVerse,
+	VerseKindChorus,
+	VerseKindBridge,
+}
+
+type VerseRepository interface {
+	GetBySongId(ctx context.Context, songId int64) ([]*Verse, error)
+	GetById(ctx context.Context, songId int64, id int64) (*Verse, error)
+	// Insert вставляет куплет на позицию verse.Position, сдвигая последующие куплеты; позиция 0 - в конец
+	Insert(ctx context.Context, songId int64, verse *Verse) error
+	Update(ctx context.Context, songId int64, id int64, verse *Verse) (*Verse, error)
+	Move(ctx context.Context, songId int64, id int64, position int) error
+	Delete(ctx context.Context, songId int64, id int64) error
+}
+
+// Verse представляет куплет текста песни
+// @Description Куплет песни. Позиция - номер куплета в песне, по ней выбирается диапазон verses в пагинации по куплетам.
+// @Tags songs
+type Verse struct {
+	// 0 у куплетов перевода, они не хранятся отдельно
+	Id       int64     `json:"id,omitempty"`
+	Position int       `json:"position"`
+	Kind     VerseKind `json:"kind"`
+	Text     string    `json:"text"`
+}
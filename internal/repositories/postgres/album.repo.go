@@ -0,0 +1,279 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type PostgresAlbumRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAlbumRepo(db *pgxpool.Pool) *PostgresAlbumRepo {
+	return &PostgresAlbumRepo{db: db}
+}
+
+const albumColumns = `al.id, al.title, al.artist_id, ar.name, al.release_date, al.cover_link`
+
+func scanAlbum(row pgx.Row) (*models.Album, error) {
+	album := models.Album{}
+	err := row.Scan(
+		&album.Id,
+		&album.Title,
+		&album.ArtistId,
+		&album.Artist,
+		&album.ReleaseDate,
+		&album.CoverLink,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &album, nil
+}
+
+func (r *PostgresAlbumRepo) GetWithPagination(ctx context.Context, limit int, offset int) ([]*models.Album, int, error) {
+	var amount int
+	query := `SELECT count(*) as amount FROM album`
+	err := r.db.QueryRow(ctx, query).Scan(&amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query = `
+		SELECT ` + albumColumns + `
+		FROM album al JOIN artist ar ON ar.id = al.artist_id
+		ORDER BY al.release_date DESC NULLS LAST, al.id ASC LIMIT $1 OFFSET $2
+	`
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	albums := make([]*models.Album, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		album, err := scanAlbum(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		albums = append(albums, album)
+	}
+
+	return albums, amount, rows.Err()
+}
+
+func (r *PostgresAlbumRepo) GetById(ctx context.Context, id int64) (*models.Album, error) {
+	query := `
+		SELECT ` + albumColumns + `
+		FROM album al JOIN artist ar ON ar.id = al.artist_id
+		WHERE al.id = $1
+	`
+	album, err := scanAlbum(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrAlbumNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT ` + songColumns + `
+		FROM ` + songFrom + `
+		WHERE s.album_id = $1
+		ORDER BY s.disc_number ASC, s.track_number ASC
+	`
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	album.Tracks, err = collectSongs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return album, nil
+}
+
+func (r *PostgresAlbumRepo) Add(ctx context.Context, album *models.Album) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	album.ArtistId, album.Artist, err = upsertArtist(ctx, tx, album.Artist)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO album (title, artist_id, release_date, cover_link)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	err = tx.QueryRow(ctx, query, album.Title, album.ArtistId, album.ReleaseDate, album.CoverLink).Scan(&album.Id)
+	if err != nil {
+		return fmt.Errorf("failed to add album: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAlbumRepo) Update(ctx context.Context, id int64, album *models.Album) (*models.Album, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	album.ArtistId, album.Artist, err = upsertArtist(ctx, tx, album.Artist)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE album
+		SET title = $1, artist_id = $2, release_date = $3, cover_link = $4, updated_at = now()
+		WHERE id = $5
+	`
+	tag, err := tx.Exec(ctx, query, album.Title, album.ArtistId, album.ReleaseDate, album.CoverLink, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update album: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		err = models.ErrAlbumNotFound
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetById(ctx, id)
+}
+
+func (r *PostgresAlbumRepo) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	// песни альбома остаются в библиотеке, но теряют привязку к треклисту
+	query := `
+		UPDATE song
+		SET album_id = NULL, disc_number = NULL, track_number = NULL, version = version + 1, updated_at = now()
+		WHERE album_id = $1
+	`
+	_, err = tx.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to detach album tracks: %w", err)
+	}
+
+	query = `
+		DELETE FROM album WHERE id = $1
+	`
+	tag, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete album: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		err = models.ErrAlbumNotFound
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAlbumRepo) SetTrack(ctx context.Context, albumId int64, songId int64, discNumber int, trackNumber int) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM album WHERE id = $1)`
+	err := r.db.QueryRow(ctx, query, albumId).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return models.ErrAlbumNotFound
+	}
+
+	query = `
+		UPDATE song
+		SET album_id = $1, disc_number = $2, track_number = $3, version = version + 1, updated_at = now()
+		WHERE id = $4 AND deleted_at IS NULL
+	`
+	tag, err := r.db.Exec(ctx, query, albumId, discNumber, trackNumber, songId)
+	if isPgError(err, pgUniqueViolation) {
+		return models.ErrAlbumTrackTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set album track: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrAlbumTrackNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresAlbumRepo) RemoveTrack(ctx context.Context, albumId int64, songId int64) error {
+	query := `
+		UPDATE song
+		SET album_id = NULL, disc_number = NULL, track_number = NULL, version = version + 1, updated_at = now()
+		WHERE id = $1 AND album_id = $2
+	`
+	tag, err := r.db.Exec(ctx, query, songId, albumId)
+	if err != nil {
+		return fmt.Errorf("failed to remove album track: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrAlbumTrackNotFound
+	}
+
+	return nil
+}
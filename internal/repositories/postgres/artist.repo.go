@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+type PostgresArtistRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresArtistRepo(db *pgxpool.Pool) *PostgresArtistRepo {
+	return &PostgresArtistRepo{db: db}
+}
+
+func (r *PostgresArtistRepo) GetWithPagination(ctx context.Context, limit int, offset int) ([]*models.Artist, int, error) {
+	var amount int
+	query := `SELECT count(*) as amount FROM artist`
+	err := r.db.QueryRow(ctx, query).Scan(&amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query = `
+		SELECT id, name
+		FROM artist
+		ORDER BY normalized_name ASC, id ASC LIMIT $1 OFFSET $2
+	`
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	artists := make([]*models.Artist, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		artist := models.Artist{}
+		err = rows.Scan(&artist.Id, &artist.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		artists = append(artists, &artist)
+	}
+
+	return artists, amount, rows.Err()
+}
+
+func (r *PostgresArtistRepo) GetById(ctx context.Context, id int64) (*models.Artist, error) {
+	artist := models.Artist{}
+
+	query := `
+		SELECT id, name FROM artist
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, id).Scan(&artist.Id, &artist.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrArtistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &artist, nil
+}
+
+func (r *PostgresArtistRepo) Add(ctx context.Context, artist *models.Artist) error {
+	query := `
+		INSERT INTO artist (name, normalized_name)
+		VALUES ($1, normalize_name($1))
+		RETURNING id, name
+	`
+
+	err := r.db.QueryRow(ctx, query, cleanName(artist.Name)).Scan(&artist.Id, &artist.Name)
+	if isPgError(err, pgUniqueViolation) {
+		return models.ErrArtistAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add artist: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresArtistRepo) Update(ctx context.Context, id int64, artist *models.Artist) (*models.Artist, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	updated := models.Artist{}
+	query := `
+		UPDATE artist
+		SET name = $1, normalized_name = normalize_name($1), updated_at = now()
+		WHERE id = $2
+		RETURNING id, name
+	`
+	err = tx.QueryRow(ctx, query, cleanName(artist.Name), id).Scan(&updated.Id, &updated.Name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrArtistNotFound
+	}
+	if isPgError(err, pgUniqueViolation) {
+		return nil, models.ErrArtistAlreadyExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update artist: %w", err)
+	}
+
+	// "group" в song - денормализованное имя артиста, поддерживаем его в актуальном состоянии
+	query = `
+		UPDATE song
+		SET "group" = $1, version = version + 1, updated_at = now()
+		WHERE artist_id = $2
+		RETURNING id, deleted_at IS NULL
+	`
+	rows, err := tx.Query(ctx, query, updated.Name, updated.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update artist songs: %w", err)
+	}
+
+	type renamedSong struct {
+		id     int64
+		active bool
+	}
+
+	renamed, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (renamedSong, error) {
+		var song renamedSong
+		err := row.Scan(&song.id, &song.active)
+		return song, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update artist songs: %w", err)
+	}
+
+	// переименование попадает в историю каждой песни. У песен в корзине ревизия не пишется,
+	// новое имя попадет в историю ревизией restore при восстановлении
+	for _, song := range renamed {
+		if !song.active {
+			continue
+		}
+
+		err = recordRevision(ctx, tx, song.id, models.RevisionUpdate, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &updated, nil
+}
+
+func (r *PostgresArtistRepo) Delete(ctx context.Context, id int64) error {
+	query := `
+		DELETE FROM artist WHERE id = $1
+	`
+	tag, err := r.db.Exec(ctx, query, id)
+	if isPgError(err, pgForeignKeyViolation) {
+		return models.ErrArtistHasSongs
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete artist: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrArtistNotFound
+	}
+
+	return nil
+}
+
+// upsertArtist находит артиста по нормализованному имени или создает нового, возвращает id и каноническое имя
+func upsertArtist(ctx context.Context, tx pgx.Tx, name string) (int64, string, error) {
+	var (
+		id        int64
+		canonical string
+	)
+
+	query := `
+		INSERT INTO artist (name, normalized_name)
+		VALUES ($1, normalize_name($1))
+		ON CONFLICT (normalized_name) DO UPDATE SET normalized_name = EXCLUDED.normalized_name
+		RETURNING id, name
+	`
+	err := tx.QueryRow(ctx, query, cleanName(name)).Scan(&id, &canonical)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to resolve artist: %w", err)
+	}
+
+	return id, canonical, nil
+}
+
+func cleanName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,251 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type PostgresEnrichmentJobRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresEnrichmentJobRepo(db *pgxpool.Pool) *PostgresEnrichmentJobRepo {
+	return &PostgresEnrichmentJobRepo{db: db}
+}
+
+func (r *PostgresEnrichmentJobRepo) Claim(ctx context.Context, lockTimeout time.Duration) (*models.EnrichmentJob, error) {
+	query := `
+		UPDATE enrichment_job
+		SET status = 'processing', attempts = attempts + 1, locked_at = now(), updated_at = now()
+		WHERE id = (
+			SELECT id FROM enrichment_job
+			WHERE ((status = 'queued' AND run_at <= now())
+				OR (status = 'processing' AND locked_at < now() - make_interval(secs => $1)))
+				-- песни в корзине не обогащаются, их задачи ждут восстановления
+				AND NOT EXISTS (SELECT 1 FROM song WHERE song.id = enrichment_job.song_id AND song.deleted_at IS NOT NULL)
+			ORDER BY run_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, song_id, status, attempts, coalesce(last_error, ''), run_at, created_at, updated_at, locked_at
+	`
+
+	job := models.EnrichmentJob{}
+	err := r.db.QueryRow(ctx, query, lockTimeout.Seconds()).Scan(
+		&job.Id,
+		&job.SongId,
+		&job.Status,
+		&job.Attempts,
+		&job.LastError,
+		&job.RunAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.LockedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim enrichment job: %w", err)
+	}
+
+	return &job, nil
+}
+
+func (r *PostgresEnrichmentJobRepo) Complete(ctx context.Context, job *models.EnrichmentJob, song *models.Song) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	query := `
+		UPDATE song
+		SET "text" = $1, "link" = $2, release_date = $3, enrichment_status = 'completed',
+			version = version + 1, updated_at = now()
+		WHERE id = $4 AND enrichment_status = 'pending' AND version = $5
+	`
+	tag, err := tx.Exec(ctx, query, song.Text, song.Link, song.ReleaseDate, job.SongId, song.Version)
+	if err != nil {
+		return fmt.Errorf("failed to update song details: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		var pending bool
+		query = `SELECT enrichment_status = 'pending' FROM song WHERE id = $1`
+		err = tx.QueryRow(ctx, query, job.SongId).Scan(&pending)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to fetch song enrichment status: %w", err)
+		}
+
+		// песню изменили после чтения воркером: детали, полученные для прежней версии, не записываются
+		if pending {
+			err = models.ErrEnrichmentSuperseded
+			return err
+		}
+
+		// песня уже не ждет обогащения (обогащена другим воркером или удалена): задача завершается как есть
+		err = finishJob(ctx, tx, job)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+
+		return nil
+	}
+
+	if song.Verses != nil {
+		err = replaceVerses(ctx, tx, job.SongId, song.Verses)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = recordRevision(ctx, tx, job.SongId, models.RevisionUpdate, nil)
+	if err != nil {
+		return err
+	}
+
+	err = finishJob(ctx, tx, job)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresEnrichmentJobRepo) Retry(ctx context.Context, job *models.EnrichmentJob, runAt time.Time, jobErr string) error {
+	query := `
+		UPDATE enrichment_job
+		SET status = 'queued', run_at = $1, last_error = $2, locked_at = NULL, updated_at = now()
+		WHERE id = $3 AND status = 'processing' AND locked_at = $4
+	`
+	tag, err := r.db.Exec(ctx, query, runAt, jobErr, job.Id, job.LockedAt)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule enrichment job: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrEnrichmentJobLost
+	}
+
+	return nil
+}
+
+func (r *PostgresEnrichmentJobRepo) Bury(ctx context.Context, job *models.EnrichmentJob, jobErr string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	query := `
+		UPDATE enrichment_job
+		SET status = 'dead', last_error = $1, locked_at = NULL, updated_at = now()
+		WHERE id = $2 AND status = 'processing' AND locked_at = $3
+	`
+	tag, err := tx.Exec(ctx, query, jobErr, job.Id, job.LockedAt)
+	if err != nil {
+		return fmt.Errorf("failed to move enrichment job to dead letter: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		err = models.ErrEnrichmentJobLost
+		return err
+	}
+
+	query = `
+		UPDATE song
+		SET enrichment_status = 'failed', version = version + 1, updated_at = now()
+		WHERE id = $1 AND enrichment_status = 'pending'
+	`
+	_, err = tx.Exec(ctx, query, job.SongId)
+	if err != nil {
+		return fmt.Errorf("failed to mark song enrichment as failed: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// finishJob завершает задачу, если она все еще занята воркером, который ее выполнил
+func finishJob(ctx context.Context, tx pgx.Tx, job *models.EnrichmentJob) error {
+	query := `
+		UPDATE enrichment_job
+		SET status = 'done', last_error = NULL, locked_at = NULL, updated_at = now()
+		WHERE id = $1 AND status = 'processing' AND locked_at = $2
+	`
+	tag, err := tx.Exec(ctx, query, job.Id, job.LockedAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete enrichment job: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrEnrichmentJobLost
+	}
+
+	return nil
+}
+
+func (r *PostgresEnrichmentJobRepo) GetLatestBySongId(ctx context.Context, songId int64) (*models.EnrichmentJob, error) {
+	query := `
+		SELECT id, song_id, status, attempts, coalesce(last_error, ''), run_at, created_at, updated_at
+		FROM enrichment_job
+		WHERE song_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	job := models.EnrichmentJob{}
+	err := r.db.QueryRow(ctx, query, songId).Scan(
+		&job.Id,
+		&job.SongId,
+		&job.Status,
+		&job.Attempts,
+		&job.LastError,
+		&job.RunAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type PostgresGenreRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresGenreRepo(db *pgxpool.Pool) *PostgresGenreRepo {
+	return &PostgresGenreRepo{db: db}
+}
+
+func (r *PostgresGenreRepo) GetAll(ctx context.Context) ([]*models.Genre, error) {
+	query := `
+		SELECT id, name, parent_id
+		FROM genre
+		ORDER BY normalized_name ASC, id ASC
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := make([]*models.Genre, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		genre := models.Genre{}
+		err = rows.Scan(&genre.Id, &genre.Name, &genre.ParentId)
+		if err != nil {
+			return nil, err
+		}
+
+		genres = append(genres, &genre)
+	}
+
+	return genres, rows.Err()
+}
+
+func (r *PostgresGenreRepo) GetById(ctx context.Context, id int64) (*models.Genre, error) {
+	genre := models.Genre{}
+
+	query := `
+		SELECT id, name, parent_id FROM genre
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(ctx, query, id).Scan(&genre.Id, &genre.Name, &genre.ParentId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrGenreNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &genre, nil
+}
+
+func (r *PostgresGenreRepo) Add(ctx context.Context, genre *models.Genre) error {
+	query := `
+		INSERT INTO genre (name, normalized_name, parent_id)
+		VALUES ($1, normalize_name($1), $2)
+		RETURNING id, name
+	`
+
+	err := r.db.QueryRow(ctx, query, cleanName(genre.Name), genre.ParentId).Scan(&genre.Id, &genre.Name)
+	if isPgError(err, pgUniqueViolation) {
+		return models.ErrGenreAlreadyExists
+	}
+	if isPgError(err, pgForeignKeyViolation) {
+		return models.ErrGenreParentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add genre: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresGenreRepo) Update(ctx context.Context, id int64, genre *models.Genre) (*models.Genre, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	// блокировка всей таблицы не дает двум конкурентным переносам собрать цикл из жанров
+	_, err = tx.Exec(ctx, `LOCK TABLE genre IN SHARE ROW EXCLUSIVE MODE`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock genres: %w", err)
+	}
+
+	if genre.ParentId != nil {
+		var cycle bool
+		query := `
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM genre WHERE id = $1
+				UNION
+				SELECT g.id FROM genre g JOIN subtree ON g.parent_id = subtree.id
+			)
+			SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)
+		`
+		err = tx.QueryRow(ctx, query, id, *genre.ParentId).Scan(&cycle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check genre hierarchy: %w", err)
+		}
+
+		if cycle {
+			err = models.ErrGenreCycle
+			return nil, err
+		}
+	}
+
+	updated := models.Genre{}
+	query := `
+		UPDATE genre
+		SET name = $1, normalized_name = normalize_name($1), parent_id = $2, updated_at = now()
+		WHERE id = $3
+		RETURNING id, name, parent_id
+	`
+	err = tx.QueryRow(ctx, query, cleanName(genre.Name), genre.ParentId, id).Scan(&updated.Id, &updated.Name, &updated.ParentId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrGenreNotFound
+	}
+	if isPgError(err, pgUniqueViolation) {
+		return nil, models.ErrGenreAlreadyExists
+	}
+	if isPgError(err, pgForeignKeyViolation) {
+		return nil, models.ErrGenreParentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update genre: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &updated, nil
+}
+
+func (r *PostgresGenreRepo) Delete(ctx context.Context, id int64) error {
+	query := `
+		DELETE FROM genre WHERE id = $1
+	`
+	tag, err := r.db.Exec(ctx, query, id)
+	if isPgError(err, pgForeignKeyViolation) {
+		return models.ErrGenreHasSubgenres
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete genre: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrGenreNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresGenreRepo) SetSongGenres(ctx context.Context, songId int64, names []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	err = lockSong(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	// каждое переданное имя должно соответствовать существующему жанру
+	query := `
+		SELECT g.id
+		FROM (SELECT DISTINCT normalize_name(n) AS normalized_name FROM unnest($1::text[]) n) names
+		LEFT JOIN genre g ON g.normalized_name = names.normalized_name
+	`
+	rows, err := tx.Query(ctx, query, names)
+	if err != nil {
+		return err
+	}
+
+	genreIds, err := pgx.CollectRows(rows, pgx.RowTo[*int64])
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(genreIds, nil) {
+		err = models.ErrGenreNotFound
+		return err
+	}
+
+	query = `DELETE FROM song_genre WHERE song_id = $1`
+	_, err = tx.Exec(ctx, query, songId)
+	if err != nil {
+		return fmt.Errorf("failed to clear song genres: %w", err)
+	}
+
+	query = `
+		INSERT INTO song_genre (song_id, genre_id)
+		SELECT $1, unnest($2::bigint[])
+	`
+	_, err = tx.Exec(ctx, query, songId, genreIds)
+	if err != nil {
+		return fmt.Errorf("failed to set song genres: %w", err)
+	}
+
+	err = bumpSongVersion(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type PostgresIdempotencyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresIdempotencyRepo(db *pgxpool.Pool) *PostgresIdempotencyRepo {
+	return &PostgresIdempotencyRepo{db: db}
+}
+
+func (r *PostgresIdempotencyRepo) Reserve(
+	ctx context.Context,
+	key string,
+	fingerprint string,
+	expiredBefore time.Time,
+	staleBefore time.Time,
+) (*models.IdempotentResponse, error) {
+	// незавершенный запрос, который завис дольше staleBefore, может занять только повтор того же запроса
+	query := `
+		INSERT INTO idempotency_key (key, fingerprint)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status = NULL, headers = '{}', body = NULL, created_at = now()
+		WHERE idempotency_key.created_at < $3
+			OR (idempotency_key.status IS NULL AND idempotency_key.created_at < $4
+				AND idempotency_key.fingerprint = EXCLUDED.fingerprint)
+		RETURNING key
+	`
+	err := r.db.QueryRow(ctx, query, key, fingerprint, expiredBefore, staleBefore).Scan(&key)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var (
+		saved    string
+		status   *int
+		response models.IdempotentResponse
+	)
+
+	query = `SELECT fingerprint, status, headers, body FROM idempotency_key WHERE key = $1`
+	err = r.db.QueryRow(ctx, query, key).Scan(&saved, &status, &response.Headers, &response.Body)
+	// ключ удален очисткой между запросами, его займет повтор
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrIdempotencyKeyInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch idempotency key: %w", err)
+	}
+
+	if saved != fingerprint {
+		return nil, models.ErrIdempotencyKeyReused
+	}
+
+	if status == nil {
+		return nil, models.ErrIdempotencyKeyInProgress
+	}
+
+	response.Status = *status
+	return &response, nil
+}
+
+func (r *PostgresIdempotencyRepo) Complete(ctx context.Context, key string, response *models.IdempotentResponse) error {
+	query := `
+		UPDATE idempotency_key SET status = $2, headers = $3, body = $4
+		WHERE key = $1
+	`
+	_, err := r.db.Exec(ctx, query, key, response.Status, response.Headers, response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresIdempotencyRepo) Release(ctx context.Context, key string) error {
+	query := `DELETE FROM idempotency_key WHERE key = $1 AND status IS NULL`
+	_, err := r.db.Exec(ctx, query, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresIdempotencyRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM idempotency_key
+		WHERE key IN (
+			SELECT key FROM idempotency_key
+			WHERE created_at < $1
+			LIMIT $2
+		)
+	`
+
+	var purged int64
+	for {
+		tag, err := r.db.Exec(ctx, query, before, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge idempotency keys: %w", err)
+		}
+
+		purged += tag.RowsAffected()
+		if tag.RowsAffected() < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"golang.org/x/text/language"
+)
+
+// searchConfigs - встроенные конфигурации полнотекстового поиска postgres по основному языку тега
+var searchConfigs = map[string]string{
+	"ar": "arabic",
+	"da": "danish",
+	"de": "german",
+	"el": "greek",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"ga": "irish",
+	"hu": "hungarian",
+	"id": "indonesian",
+	"it": "italian",
+	"lt": "lithuanian",
+	"ne": "nepali",
+	"nb": "norwegian",
+	"nl": "dutch",
+	"nn": "norwegian",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"ta": "tamil",
+	"tr": "turkish",
+}
+
+// searchConfig выбирает конфигурацию поиска для языка. Транслитерация (ru-Latn) и языки без своей
+// конфигурации индексируются простой конфигурацией simple, без стемминга
+func searchConfig(lang string) string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return "simple"
+	}
+
+	base, _ := tag.Base()
+	script, _ := tag.Script()
+	if defaultScript, _ := language.Make(base.String()).Script(); script != defaultScript {
+		return "simple"
+	}
+
+	if config, ok := searchConfigs[base.String()]; ok {
+		return config
+	}
+
+	return "simple"
+}
+
+// langMatch сравнивает язык варианта текста с языком запроса: en совпадает с en и en-GB
+func langMatch(column string, placeholder string) string {
+	return `(` + column + ` = ` + placeholder + ` OR ` + column + ` LIKE ` + placeholder + ` || '-%')`
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type PostgresLyricsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresLyricsRepo(db *pgxpool.Pool) *PostgresLyricsRepo {
+	return &PostgresLyricsRepo{db: db}
+}
+
+func (r *PostgresLyricsRepo) GetLines(ctx context.Context, songId int64) ([]*models.LyricsLine, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM song WHERE id = $1 AND deleted_at IS NULL)`
+	err := r.db.QueryRow(ctx, query, songId).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, models.ErrSongNotFound
+	}
+
+	query = `
+		SELECT position, time_ms, "text"
+		FROM lyrics_line
+		WHERE song_id = $1
+		ORDER BY position
+	`
+	rows, err := r.db.Query(ctx, query, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*models.LyricsLine, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		line := &models.LyricsLine{}
+		err = rows.Scan(&line.Position, &line.TimeMs, &line.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+func (r *PostgresLyricsRepo) ReplaceLines(ctx context.Context, songId int64, lines []*models.LyricsLine, verses []*models.Verse) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	err = lockSong(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	// replaceVerses удаляет прежние строки вместе с прежним текстом, поэтому новые строки пишутся после него
+	err = replaceVerses(ctx, tx, songId, verses)
+	if err != nil {
+		return err
+	}
+
+	times := make([]int64, 0, len(lines))
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		times = append(times, line.TimeMs)
+		texts = append(texts, line.Text)
+	}
+
+	query := `
+		INSERT INTO lyrics_line (song_id, position, time_ms, "text")
+		SELECT $1, l.position, l.time_ms, l."text"
+		FROM unnest($2::bigint[], $3::text[]) WITH ORDINALITY AS l(time_ms, "text", position)
+	`
+	_, err = tx.Exec(ctx, query, songId, times, texts)
+	if err != nil {
+		return fmt.Errorf("failed to add lyrics lines: %w", err)
+	}
+
+	err = bumpSongVersion(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	err = recordRevision(ctx, tx, songId, models.RevisionUpdate, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresLyricsRepo) GetVariants(ctx context.Context, songId int64) ([]*models.LyricsVariant, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM song WHERE id = $1 AND deleted_at IS NULL)`
+	err := r.db.QueryRow(ctx, query, songId).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, models.ErrSongNotFound
+	}
+
+	query = `
+		SELECT lang, kind, "text", created_at, updated_at
+		FROM lyrics_variant
+		WHERE song_id = $1
+		ORDER BY lang
+	`
+	rows, err := r.db.Query(ctx, query, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make([]*models.LyricsVariant, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		variant := &models.LyricsVariant{}
+		err = rows.Scan(&variant.Lang, &variant.Kind, &variant.Text, &variant.CreatedAt, &variant.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, variant)
+	}
+
+	return variants, rows.Err()
+}
+
+func (r *PostgresLyricsRepo) SaveVariant(ctx context.Context, songId int64, variant *models.LyricsVariant) (bool, error) {
+	// xmax = 0 только у вставленной строки, у обновленной в xmax записана текущая транзакция
+	query := `
+		INSERT INTO lyrics_variant (song_id, lang, kind, "text", search_config)
+		VALUES ($1, $2, $3, $4, $5::regconfig)
+		ON CONFLICT (song_id, lang) DO UPDATE
+		SET kind = excluded.kind, "text" = excluded."text", search_config = excluded.search_config, updated_at = now()
+		RETURNING created_at, updated_at, xmax = 0
+	`
+
+	var created bool
+	err := r.db.QueryRow(ctx, query, songId, variant.Lang, variant.Kind, variant.Text, searchConfig(variant.Lang)).Scan(
+		&variant.CreatedAt,
+		&variant.UpdatedAt,
+		&created,
+	)
+	if isPgError(err, pgForeignKeyViolation) {
+		return false, models.ErrSongNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to save lyrics variant: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *PostgresLyricsRepo) DeleteVariant(ctx context.Context, songId int64, lang string) error {
+	query := `DELETE FROM lyrics_variant WHERE song_id = $1 AND lang = $2`
+	tag, err := r.db.Exec(ctx, query, songId, lang)
+	if err != nil {
+		return fmt.Errorf("failed to delete lyrics variant: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrLyricsVariantNotFound
+	}
+
+	return nil
+}
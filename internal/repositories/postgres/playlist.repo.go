@@ -0,0 +1,431 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type PostgresPlaylistRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresPlaylistRepo(db *pgxpool.Pool) *PostgresPlaylistRepo {
+	return &PostgresPlaylistRepo{db: db}
+}
+
+const playlistColumns = `
+	p.id, p.name,
+	(
+		SELECT count(*)
+		FROM playlist_entry e JOIN song s ON s.id = e.song_id
+		WHERE e.playlist_id = p.id AND s.deleted_at IS NULL
+	),
+	p.created_at, p.updated_at
+`
+
+func scanPlaylist(row pgx.Row) (*models.Playlist, error) {
+	playlist := models.Playlist{}
+	err := row.Scan(
+		&playlist.Id,
+		&playlist.Name,
+		&playlist.EntriesAmount,
+		&playlist.CreatedAt,
+		&playlist.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &playlist, nil
+}
+
+func (r *PostgresPlaylistRepo) GetWithPagination(ctx context.Context, limit int, offset int) ([]*models.Playlist, int, error) {
+	var amount int
+	query := `SELECT count(*) as amount FROM playlist`
+	err := r.db.QueryRow(ctx, query).Scan(&amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query = `
+		SELECT ` + playlistColumns + `
+		FROM playlist p
+		ORDER BY p.created_at DESC, p.id DESC LIMIT $1 OFFSET $2
+	`
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	playlists := make([]*models.Playlist, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		playlist, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		playlists = append(playlists, playlist)
+	}
+
+	return playlists, amount, rows.Err()
+}
+
+func (r *PostgresPlaylistRepo) GetById(ctx context.Context, id int64) (*models.Playlist, error) {
+	query := `
+		SELECT ` + playlistColumns + `
+		FROM playlist p
+		WHERE p.id = $1
+	`
+
+	playlist, err := scanPlaylist(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrPlaylistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
+
+func (r *PostgresPlaylistRepo) GetEntriesWithPagination(
+	ctx context.Context,
+	playlistId int64,
+	limit int, offset int,
+) ([]*models.PlaylistEntry, int, error) {
+	var amount int
+	// записи с песнями из корзины скрыты, пока песню не восстановят или не удалят окончательно
+	query := `
+		SELECT count(*) as amount
+		FROM playlist_entry e JOIN song s ON s.id = e.song_id
+		WHERE e.playlist_id = $1 AND s.deleted_at IS NULL
+	`
+	err := r.db.QueryRow(ctx, query, playlistId).Scan(&amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// позиции нумеруются подряд среди видимых записей, скрытые записи в нумерации не участвуют
+	query = `
+		SELECT e.id, row_number() OVER (ORDER BY e.position ASC, e.id ASC), e.added_at, ` + songColumns + `
+		FROM playlist_entry e
+		JOIN ` + songFrom + ` ON s.id = e.song_id
+		WHERE e.playlist_id = $1
+		ORDER BY e.position ASC, e.id ASC LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(ctx, query, playlistId, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]*models.PlaylistEntry, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		entry := models.PlaylistEntry{}
+		sc := songScanner{}
+
+		targets := append([]any{&entry.Id, &entry.Position, &entry.AddedAt}, sc.targets()...)
+		err = rows.Scan(targets...)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		entry.Song = sc.result()
+		entries = append(entries, &entry)
+	}
+
+	return entries, amount, rows.Err()
+}
+
+func (r *PostgresPlaylistRepo) Add(ctx context.Context, playlist *models.Playlist) error {
+	query := `
+		INSERT INTO playlist (name)
+		VALUES ($1)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query, playlist.Name).Scan(&playlist.Id, &playlist.CreatedAt, &playlist.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add playlist: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresPlaylistRepo) Rename(ctx context.Context, id int64, name string) (*models.Playlist, error) {
+	query := `
+		UPDATE playlist
+		SET name = $1, updated_at = now()
+		WHERE id = $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, name, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename playlist: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return nil, models.ErrPlaylistNotFound
+	}
+
+	return r.GetById(ctx, id)
+}
+
+func (r *PostgresPlaylistRepo) Delete(ctx context.Context, id int64) error {
+	query := `
+		DELETE FROM playlist WHERE id = $1
+	`
+
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete playlist: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrPlaylistNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresPlaylistRepo) InsertEntry(ctx context.Context, playlistId int64, songId int64, position int) (*models.PlaylistEntry, error) {
+	var entry *models.PlaylistEntry
+
+	err := r.editEntries(ctx, playlistId, func(tx pgx.Tx, amount int) error {
+		var stored int
+		var err error
+		if position <= 0 || position > amount {
+			position = amount + 1
+
+			query := `SELECT coalesce(max(position), 0) + 1 FROM playlist_entry WHERE playlist_id = $1`
+			err = tx.QueryRow(ctx, query, playlistId).Scan(&stored)
+		} else {
+			stored, err = storedEntryPosition(ctx, tx, playlistId, position)
+		}
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE playlist_entry
+			SET position = position + 1
+			WHERE playlist_id = $1 AND position >= $2
+		`
+		_, err = tx.Exec(ctx, query, playlistId, stored)
+		if err != nil {
+			return fmt.Errorf("failed to shift playlist entries: %w", err)
+		}
+
+		entry = &models.PlaylistEntry{Position: position}
+		query = `
+			INSERT INTO playlist_entry (playlist_id, song_id, position)
+			SELECT $1, id, $3 FROM song WHERE id = $2 AND deleted_at IS NULL
+			RETURNING id, added_at
+		`
+		err = tx.QueryRow(ctx, query, playlistId, songId, stored).Scan(&entry.Id, &entry.AddedAt)
+		if errors.Is(err, pgx.ErrNoRows) || isPgError(err, pgForeignKeyViolation) {
+			return models.ErrPlaylistSongNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add playlist entry: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *PostgresPlaylistRepo) MoveEntry(ctx context.Context, playlistId int64, entryId int64, position int) error {
+	return r.editEntries(ctx, playlistId, func(tx pgx.Tx, amount int) error {
+		var current int
+		query := `
+			SELECT e.position
+			FROM playlist_entry e JOIN song s ON s.id = e.song_id
+			WHERE e.id = $1 AND e.playlist_id = $2 AND s.deleted_at IS NULL
+		`
+		err := tx.QueryRow(ctx, query, entryId, playlistId).Scan(&current)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrPlaylistEntryNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// запись встает на место видимой записи с той же позицией, скрытые записи сдвигаются вместе с остальными
+		target, err := storedEntryPosition(ctx, tx, playlistId, min(max(position, 1), amount))
+		if err != nil {
+			return err
+		}
+
+		if target == current {
+			return nil
+		}
+
+		if target < current {
+			query = `
+				UPDATE playlist_entry
+				SET position = position + 1
+				WHERE playlist_id = $1 AND position >= $2 AND position < $3
+			`
+		} else {
+			query = `
+				UPDATE playlist_entry
+				SET position = position - 1
+				WHERE playlist_id = $1 AND position > $3 AND position <= $2
+			`
+		}
+
+		_, err = tx.Exec(ctx, query, playlistId, target, current)
+		if err != nil {
+			return fmt.Errorf("failed to shift playlist entries: %w", err)
+		}
+
+		query = `UPDATE playlist_entry SET position = $1 WHERE id = $2`
+		_, err = tx.Exec(ctx, query, target, entryId)
+		if err != nil {
+			return fmt.Errorf("failed to move playlist entry: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *PostgresPlaylistRepo) RemoveEntry(ctx context.Context, playlistId int64, entryId int64) error {
+	return r.editEntries(ctx, playlistId, func(tx pgx.Tx, _ int) error {
+		query := `DELETE FROM playlist_entry WHERE id = $1 AND playlist_id = $2`
+		tag, err := tx.Exec(ctx, query, entryId, playlistId)
+		if err != nil {
+			return fmt.Errorf("failed to remove playlist entry: %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return models.ErrPlaylistEntryNotFound
+		}
+
+		return nil
+	})
+}
+
+// editEntries выполняет изменение записей плейлиста в транзакции под блокировкой строки плейлиста,
+// поэтому конкурентные правки одного плейлиста выполняются строго по очереди.
+// До и после изменения позиции перенумеровываются подряд с 1.
+// В edit передается число видимых записей: записи с песнями из корзины в нумерации для клиента не участвуют.
+func (r *PostgresPlaylistRepo) editEntries(ctx context.Context, playlistId int64, edit func(tx pgx.Tx, amount int) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	query := `SELECT id FROM playlist WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRow(ctx, query, playlistId).Scan(&playlistId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrPlaylistNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock playlist: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `SET CONSTRAINTS playlist_entry_position_key DEFERRED`)
+	if err != nil {
+		return fmt.Errorf("failed to defer playlist constraints: %w", err)
+	}
+
+	// удаление песен из библиотеки оставляет дыры в нумерации, закрываем их до правки
+	err = renumberPlaylistEntries(ctx, tx, playlistId)
+	if err != nil {
+		return err
+	}
+
+	var amount int
+	query = `
+		SELECT count(*)
+		FROM playlist_entry e JOIN song s ON s.id = e.song_id
+		WHERE e.playlist_id = $1 AND s.deleted_at IS NULL
+	`
+	err = tx.QueryRow(ctx, query, playlistId).Scan(&amount)
+	if err != nil {
+		return err
+	}
+
+	err = edit(tx, amount)
+	if err != nil {
+		return err
+	}
+
+	err = renumberPlaylistEntries(ctx, tx, playlistId)
+	if err != nil {
+		return err
+	}
+
+	query = `UPDATE playlist SET updated_at = now() WHERE id = $1`
+	_, err = tx.Exec(ctx, query, playlistId)
+	if err != nil {
+		return fmt.Errorf("failed to touch playlist: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// storedEntryPosition переводит позицию среди видимых записей в хранимую позицию записи
+func storedEntryPosition(ctx context.Context, tx pgx.Tx, playlistId int64, position int) (int, error) {
+	var stored int
+	query := `
+		SELECT e.position
+		FROM playlist_entry e JOIN song s ON s.id = e.song_id
+		WHERE e.playlist_id = $1 AND s.deleted_at IS NULL
+		ORDER BY e.position ASC
+		OFFSET $2 LIMIT 1
+	`
+
+	err := tx.QueryRow(ctx, query, playlistId, position-1).Scan(&stored)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find playlist entry position: %w", err)
+	}
+
+	return stored, nil
+}
+
+func renumberPlaylistEntries(ctx context.Context, tx pgx.Tx, playlistId int64) error {
+	query := `
+		UPDATE playlist_entry e
+		SET position = n.position
+		FROM (
+			SELECT id, row_number() OVER (ORDER BY position ASC, id ASC) AS position
+			FROM playlist_entry
+			WHERE playlist_id = $1
+		) n
+		WHERE e.id = n.id AND e.position <> n.position
+	`
+
+	_, err := tx.Exec(ctx, query, playlistId)
+	if err != nil {
+		return fmt.Errorf("failed to renumber playlist entries: %w", err)
+	}
+
+	return nil
+}
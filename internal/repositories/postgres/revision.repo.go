@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+// songSnapshot - редактируемые поля строки song в виде снимка ревизии, см. models.SongSnapshot
+const songSnapshot = `jsonb_build_object(
+	'song', song,
+	'group', "group",
+	'text', "text",
+	'link', "link",
+	'release_date', release_date,
+	'lang', lang
+)`
+
+type PostgresSongRevisionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresSongRevisionRepo(db *pgxpool.Pool) *PostgresSongRevisionRepo {
+	return &PostgresSongRevisionRepo{db: db}
+}
+
+func (r *PostgresSongRevisionRepo) GetBySongId(
+	ctx context.Context,
+	songId int64,
+	limit int, offset int,
+) ([]*models.SongRevision, int, error) {
+	var amount int
+	query := `SELECT count(*) FROM song_revision WHERE song_id = $1`
+	err := r.db.QueryRow(ctx, query, songId).Scan(&amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if amount == 0 {
+		return nil, 0, models.ErrSongNotFound
+	}
+
+	query = `
+		SELECT revision, song_id, action, coalesce(actor, ''), changed, restored_from, created_at
+		FROM song_revision
+		WHERE song_id = $1
+		ORDER BY revision DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(ctx, query, songId, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	revisions := make([]*models.SongRevision, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		revision := &models.SongRevision{}
+		err = rows.Scan(
+			&revision.Revision,
+			&revision.SongId,
+			&revision.Action,
+			&revision.Actor,
+			&revision.Changed,
+			&revision.RestoredFrom,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, amount, rows.Err()
+}
+
+func (r *PostgresSongRevisionRepo) GetByRevision(ctx context.Context, songId int64, revision int) (*models.SongRevision, error) {
+	query := `
+		SELECT revision, song_id, action, coalesce(actor, ''), changed, restored_from, snapshot, created_at
+		FROM song_revision
+		WHERE song_id = $1 AND revision = $2
+	`
+
+	found := &models.SongRevision{}
+	err := r.db.QueryRow(ctx, query, songId, revision).Scan(
+		&found.Revision,
+		&found.SongId,
+		&found.Action,
+		&found.Actor,
+		&found.Changed,
+		&found.RestoredFrom,
+		&found.Snapshot,
+		&found.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrSongRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+func (r *PostgresSongRevisionRepo) Restore(
+	ctx context.Context,
+	songId int64,
+	revision int,
+	song *models.Song,
+	ifMatch []int,
+) (*models.Song, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	err = lockSongIfMatch(ctx, tx, songId, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	song.ArtistId, song.Group, err = upsertArtist(ctx, tx, song.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE song
+		SET song = $1, "group" = $2, artist_id = $3, "link" = $4, "text" = $5, release_date = $6, lang = nullif($7, ''),
+			version = version + 1, updated_at = now()
+		WHERE id = $8
+	`
+	_, err = tx.Exec(ctx, query, song.Song, song.Group, song.ArtistId, song.Link, song.Text, song.ReleaseDate, song.Lang, songId)
+	if isSongTitleTaken(err) {
+		err = songExistsError(ctx, r.db, song.ArtistId, song.Song)
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore song: %w", err)
+	}
+
+	err = replaceVerses(ctx, tx, songId, song.Verses)
+	if err != nil {
+		return nil, err
+	}
+
+	err = recordRevision(ctx, tx, songId, models.RevisionRestore, &revision)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	query = `SELECT ` + songColumns + ` FROM ` + songFrom + ` WHERE s.id = $1`
+	return scanSong(r.db.QueryRow(ctx, query, songId))
+}
+
+// recordRevision добавляет ревизию с текущим состоянием песни в транзакции правки. Автор берется из контекста.
+// Правка без изменений снимка не записывается, остальные действия записываются всегда
+func recordRevision(ctx context.Context, tx pgx.Tx, songId int64, action models.SongRevisionAction, restoredFrom *int) error {
+	err := lockSong(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		WITH cur AS (
+			SELECT ` + songSnapshot + ` AS snapshot FROM song WHERE id = $1
+		),
+		prev AS (
+			SELECT revision, snapshot FROM song_revision
+			WHERE song_id = $1
+			ORDER BY revision DESC
+			LIMIT 1
+		),
+		diff AS (
+			SELECT ARRAY(
+				SELECT c.key FROM jsonb_each(cur.snapshot) c
+				WHERE c.value IS DISTINCT FROM (SELECT prev.snapshot -> c.key FROM prev)
+				ORDER BY c.key
+			) AS changed
+			FROM cur
+		)
+		INSERT INTO song_revision (song_id, revision, action, actor, snapshot, changed, restored_from)
+		SELECT $1, coalesce((SELECT revision FROM prev), 0) + 1, $2, nullif($3, ''), cur.snapshot, diff.changed, $4
+		FROM cur, diff
+		WHERE $2 <> '` + string(models.RevisionUpdate) + `' OR cardinality(diff.changed) > 0
+	`
+	_, err = tx.Exec(ctx, query, songId, action, models.ActorFromContext(ctx), restoredFrom)
+	if err != nil {
+		return fmt.Errorf("failed to record song revision: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+// whereBuilder собирает WHERE из условий, нумеруя плейсхолдеры по порядку добавления аргументов
+type whereBuilder struct {
+	conds []string
+	args  []any
+}
+
+// arg добавляет аргумент запроса и возвращает его плейсхолдер
+func (w *whereBuilder) arg(value any) string {
+	w.args = append(w.args, value)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+func (w *whereBuilder) and(cond string) {
+	w.conds = append(w.conds, cond)
+}
+
+func (w *whereBuilder) sql() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// songWhere строит условия выборки песен по фильтру
+func songWhere(filter models.SongFilter) *whereBuilder {
+	w := &whereBuilder{}
+
+	if filter.SearchQuery != "" && filter.Lang == "" {
+		w.and(songDocument + ` @@ websearch_to_tsquery(` + w.arg(filter.SearchQuery) + `)`)
+	}
+
+	if filter.SearchQuery != "" && filter.Lang != "" {
+		config := w.arg(searchConfig(filter.Lang)) + `::regconfig`
+		query := `websearch_to_tsquery(` + config + `, ` + w.arg(filter.SearchQuery) + `)`
+		w.and(`((s.search_config = ` + config + ` AND ` + songDocument + ` @@ ` + query + `) OR EXISTS (
+			SELECT 1 FROM lyrics_variant lv
+			WHERE lv.song_id = s.id AND ` + langMatch(`lv.lang`, w.arg(filter.Lang)) + ` AND lv.search_vector @@ ` + query + `
+		))`)
+	}
+
+	if filter.Fuzzy != "" {
+		// <% сравнивает запрос с самым похожим отрывком поля и использует триграммные индексы
+		query := w.arg(filter.Fuzzy)
+		w.and(`(normalize_name(` + query + `) <% normalize_name(s.song) OR normalize_name(` + query + `) <% normalize_name(s."group"))`)
+	}
+
+	if filter.Group != "" {
+		w.and(`s.artist_id IN (SELECT id FROM artist WHERE ` + textMatch("normalized_name", filter.GroupMatch, w.arg(filter.Group)) + `)`)
+	}
+
+	if filter.Song != "" {
+		w.and(textMatch("normalize_name(s.song)", filter.SongMatch, w.arg(filter.Song)))
+	}
+
+	if filter.ReleaseDateFrom != nil {
+		w.and(songReleaseDate + ` >= ` + w.arg(*filter.ReleaseDateFrom) + `::date`)
+	}
+
+	if filter.ReleaseDateTo != nil {
+		w.and(songReleaseDate + ` <= ` + w.arg(*filter.ReleaseDateTo) + `::date`)
+	}
+
+	if filter.HasLink != nil {
+		if *filter.HasLink {
+			w.and(`s."link" <> ''`)
+		} else {
+			w.and(`s."link" = ''`)
+		}
+	}
+
+	if filter.CreatedAfter != nil {
+		w.and(`s.created_at > ` + w.arg(*filter.CreatedAfter))
+	}
+
+	if filter.UpdatedAfter != nil {
+		w.and(`s.updated_at > ` + w.arg(*filter.UpdatedAfter))
+	}
+
+	for _, names := range matchGroups(filter.Genres, filter.GenreMatch) {
+		w.and(`EXISTS (
+			SELECT 1 FROM song_genre sg
+			WHERE sg.song_id = s.id AND sg.genre_id IN (
+				WITH RECURSIVE subtree AS (
+					SELECT id FROM genre
+					WHERE normalized_name IN (SELECT normalize_name(n) FROM unnest(` + w.arg(names) + `::text[]) n)
+					UNION
+					SELECT g.id FROM genre g JOIN subtree ON g.parent_id = subtree.id
+				)
+				SELECT id FROM subtree
+			)
+		)`)
+	}
+
+	for _, names := range matchGroups(filter.Tags, filter.TagMatch) {
+		w.and(`EXISTS (
+			SELECT 1 FROM song_tag st JOIN tag t ON t.id = st.tag_id
+			WHERE st.song_id = s.id
+				AND t.normalized_name IN (SELECT normalize_name(n) FROM unnest(` + w.arg(names) + `::text[]) n)
+		)`)
+	}
+
+	return w
+}
+
+const (
+	// короткие поля подсвечиваются целиком
+	headlineFieldOptions = `StartSel=<mark>, StopSel=</mark>, HighlightAll=true`
+	headlineTextOptions  = `StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=15, MinWords=5, FragmentDelimiter=" ... "`
+)
+
+// songHighlight возвращает дополнительные колонки с подсвеченными совпадениями поискового запроса
+// для songScanner.highlight, без запроса - пустую строку
+func songHighlight(filter models.SongFilter, w *whereBuilder) string {
+	if filter.SearchQuery == "" {
+		return ""
+	}
+
+	query := searchTsQuery(filter, w)
+	return `,
+		ts_headline(s.search_config, s.song, ` + query + `, '` + headlineFieldOptions + `'),
+		ts_headline(s.search_config, s."group", ` + query + `, '` + headlineFieldOptions + `'),
+		ts_headline(s.search_config, s."text", ` + query + `, '` + headlineTextOptions + `')`
+}
+
+// searchTsQuery разбирает поисковый запрос конфигурацией языка поиска, без языка - конфигурацией соединения
+func searchTsQuery(filter models.SongFilter, w *whereBuilder) string {
+	if filter.Lang == "" {
+		return `websearch_to_tsquery(` + w.arg(filter.SearchQuery) + `)`
+	}
+
+	return `websearch_to_tsquery(` + w.arg(searchConfig(filter.Lang)) + `::regconfig, ` + w.arg(filter.SearchQuery) + `)`
+}
+
+// searchRank - релевантность песни поисковому запросу. С языком поиска учитываются и варианты текста на этом языке
+func searchRank(filter models.SongFilter, w *whereBuilder) string {
+	query := searchTsQuery(filter, w)
+	if filter.Lang == "" {
+		return `ts_rank(` + songDocument + `, ` + query + `)`
+	}
+
+	return `greatest(ts_rank(` + songDocument + `, ` + query + `), coalesce((
+		SELECT max(ts_rank(lv.search_vector, ` + query + `)) FROM lyrics_variant lv
+		WHERE lv.song_id = s.id AND ` + langMatch(`lv.lang`, w.arg(filter.Lang)) + `
+	), 0))`
+}
+
+// songMatch возвращает дополнительные колонки с полем, лучше всего совпавшим с нечетким запросом,
+// и его сходством для songScanner.match, без запроса - пустую строку
+func songMatch(filter models.SongFilter, w *whereBuilder) string {
+	if filter.Fuzzy == "" {
+		return ""
+	}
+
+	query := w.arg(filter.Fuzzy)
+	return matchColumns(wordSimilarity(`s.song`, query), wordSimilarity(`s."group"`, query))
+}
+
+func matchColumns(songSimilarity string, groupSimilarity string) string {
+	return `,
+		CASE WHEN ` + songSimilarity + ` >= ` + groupSimilarity + ` THEN '` + string(models.MatchFieldSong) + `' ELSE '` + string(models.MatchFieldGroup) + `' END,
+		greatest(` + songSimilarity + `, ` + groupSimilarity + `)`
+}
+
+// wordSimilarity - сходство запроса с самым похожим отрывком нормализованного поля, от 0 до 1
+func wordSimilarity(column string, placeholder string) string {
+	return `word_similarity(normalize_name(` + placeholder + `), normalize_name(` + column + `))::float8`
+}
+
+// withinDistance проверяет, что все слова запроса text встречаются в векторе vector
+// в пределах distance позиций от какого-то слова вектора, то есть в одном окне из distance+1 слов
+func withinDistance(vector string, text string, distance string) string {
+	return `EXISTS (
+		SELECT 1 FROM unnest(` + vector + `) AS head(lexeme, positions, weights), unnest(head.positions) AS start(pos)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM unnest(tsvector_to_array(to_tsvector(` + text + `))) AS word(lexeme)
+			WHERE NOT EXISTS (
+				SELECT 1 FROM unnest(` + vector + `) AS l(lexeme, positions, weights), unnest(l.positions) AS p(pos)
+				WHERE l.lexeme = word.lexeme AND p.pos BETWEEN start.pos AND start.pos + ` + distance + `::int
+			)
+		)
+	)`
+}
+
+// orderTerm - выражение сортировки, ключи сортировки песен раскладываются на одно или несколько выражений
+type orderTerm struct {
+	expr string
+	desc bool
+}
+
+// songOrder переводит ключи сортировки в выражения по белому списку полей и добавляет id для стабильного порядка.
+// Аргументы выражений (запрос для релевантности) добавляются в w.
+func songOrder(sort []models.SongSortKey, filter models.SongFilter, w *whereBuilder) ([]orderTerm, error) {
+	sort = models.EffectiveSongSort(sort, filter)
+
+	terms := make([]orderTerm, 0, len(sort)+1)
+	for _, key := range sort {
+		switch key.Field {
+		case models.SortByReleaseDate:
+			// песни без даты выпуска всегда в конце, независимо от направления
+			terms = append(terms,
+				orderTerm{expr: `(` + songReleaseDate + ` IS NULL)`},
+				orderTerm{expr: `coalesce(` + songReleaseDate + `, '-infinity'::date)`, desc: key.Desc},
+			)
+		case models.SortByCreatedAt:
+			terms = append(terms, orderTerm{expr: `s.created_at`, desc: key.Desc})
+		case models.SortByUpdatedAt:
+			terms = append(terms, orderTerm{expr: `s.updated_at`, desc: key.Desc})
+		case models.SortBySong:
+			terms = append(terms, orderTerm{expr: `s.song`, desc: key.Desc})
+		case models.SortByGroup:
+			terms = append(terms, orderTerm{expr: `s."group"`, desc: key.Desc})
+		case models.SortByRelevance:
+			if filter.SearchQuery == "" {
+				return nil, fmt.Errorf("%w: relevance requires a search query", models.ErrInvalidSongSort)
+			}
+
+			terms = append(terms, orderTerm{expr: searchRank(filter, w), desc: key.Desc})
+		case models.SortBySimilarity:
+			if filter.Fuzzy == "" {
+				return nil, fmt.Errorf("%w: similarity requires a fuzzy query", models.ErrInvalidSongSort)
+			}
+
+			query := w.arg(filter.Fuzzy)
+			terms = append(terms, orderTerm{
+				expr: `greatest(` + wordSimilarity(`s.song`, query) + `, ` + wordSimilarity(`s."group"`, query) + `)`,
+				desc: key.Desc,
+			})
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", models.ErrInvalidSongSort, key.Field)
+		}
+	}
+
+	return append(terms, orderTerm{expr: `s.id`}), nil
+}
+
+func orderBy(terms []orderTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		direction := " ASC"
+		if term.desc {
+			direction = " DESC"
+		}
+
+		parts = append(parts, term.expr+direction)
+	}
+
+	return "ORDER BY " + strings.Join(parts, ", ")
+}
+
+// reversed возвращает выражения с обратным направлением сортировки
+func reversed(terms []orderTerm) []orderTerm {
+	result := make([]orderTerm, 0, len(terms))
+	for _, term := range terms {
+		result = append(result, orderTerm{expr: term.expr, desc: !term.desc})
+	}
+
+	return result
+}
+
+// orderValues возвращает выражения сортировки в текстовом виде для сохранения в курсоре
+func orderValues(terms []orderTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, `(`+term.expr+`)::text`)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// keysetCondition отбирает строки, стоящие в порядке terms строго после строки со значениями values.
+// Направления ключей могут различаться, поэтому сравнение кортежей раскрывается в цепочку OR.
+// Значения передаются текстом, тип параметра postgres выводит из выражения.
+func keysetCondition(terms []orderTerm, values []string, w *whereBuilder) string {
+	ors := make([]string, 0, len(terms))
+	for i, term := range terms {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, terms[j].expr+` = `+w.arg(values[j]))
+		}
+
+		op := ` > `
+		if term.desc {
+			op = ` < `
+		}
+
+		ands = append(ands, term.expr+op+w.arg(values[i]))
+		ors = append(ors, `(`+strings.Join(ands, ` AND `)+`)`)
+	}
+
+	return `(` + strings.Join(ors, ` OR `) + `)`
+}
+
+// textMatch сравнивает уже нормализованную колонку с нормализованным значением целиком или по началу строки
+func textMatch(column string, match models.TextMatch, placeholder string) string {
+	if match == models.MatchPrefix {
+		return `starts_with(` + column + `, normalize_name(` + placeholder + `))`
+	}
+
+	return column + ` = normalize_name(` + placeholder + `)`
+}
+
+// matchGroups раскладывает значения фильтра на группы, каждая из которых должна совпасть хотя бы одним значением
+func matchGroups(values []string, match models.ClassificationMatch) [][]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	if match != models.MatchAll {
+		return [][]string{values}
+	}
+
+	groups := make([][]string, 0, len(values))
+	for _, value := range values {
+		groups = append(groups, []string{value})
+	}
+
+	return groups
+}
@@ -2,10 +2,13 @@ package repositories
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shlmvgleb/em-task/internal/models"
 )
@@ -18,72 +21,597 @@ func NewPostgresSongRepo(db *pgxpool.Pool) *PostgresSongRepo {
 	return &PostgresSongRepo{db: db}
 }
 
-func (r *PostgresSongRepo) GetWithSearchAndPagination(
+const (
+	songColumns = `
+		s.id, s.song, s."group", s.artist_id, s."text", s.release_date, a.release_date,
+		s."link", s.enrichment_status, s.album_id, s.disc_number, s.track_number,
+		ARRAY(
+			SELECT g.name FROM song_genre sg JOIN genre g ON g.id = sg.genre_id
+			WHERE sg.song_id = s.id ORDER BY g.normalized_name
+		),
+		ARRAY(
+			SELECT t.name FROM song_tag st JOIN tag t ON t.id = st.tag_id
+			WHERE st.song_id = s.id ORDER BY t.normalized_name
+		),
+		coalesce(s.lang, ''), s.version
+	`
+	// песни вне корзины; все выборки песен идут через songFrom или явно проверяют s.deleted_at
+	songFrom = `(SELECT * FROM song WHERE deleted_at IS NULL) s LEFT JOIN album a ON a.id = s.album_id`
+	// песни в корзине
+	trashFrom = `song s LEFT JOIN album a ON a.id = s.album_id`
+	// дата выпуска песни с фолбэком на дату альбома
+	songReleaseDate = `coalesce(s.release_date, a.release_date)`
+	// документ полнотекстового поиска по песне: название с весом A, исполнитель - B, текст - C
+	songDocument = `s.search_vector`
+)
+
+// songScanner собирает песню из колонок songColumns, в том числе при выборке вместе с другими колонками
+type songScanner struct {
+	song             models.Song
+	albumReleaseDate *time.Time
+	// не nil, если за колонками songColumns выбраны колонки songHighlight
+	highlight *models.SongHighlight
+	// не nil, если следом выбраны колонки songMatch
+	match *models.SongMatch
+}
+
+// newListScanner возвращает сканер песни из списка, при поиске - вместе с подсветкой и нечетким совпадением
+func newListScanner(filter models.SongFilter) songScanner {
+	sc := songScanner{}
+	if filter.SearchQuery != "" {
+		sc.highlight = &models.SongHighlight{}
+	}
+
+	if filter.Fuzzy != "" {
+		sc.match = &models.SongMatch{}
+	}
+
+	return sc
+}
+
+func (sc *songScanner) targets() []any {
+	targets := []any{
+		&sc.song.Id,
+		&sc.song.Song,
+		&sc.song.Group,
+		&sc.song.ArtistId,
+		&sc.song.Text,
+		&sc.song.ReleaseDate,
+		&sc.albumReleaseDate,
+		&sc.song.Link,
+		&sc.song.EnrichmentStatus,
+		&sc.song.AlbumId,
+		&sc.song.DiscNumber,
+		&sc.song.TrackNumber,
+		&sc.song.Genres,
+		&sc.song.Tags,
+		&sc.song.Lang,
+		&sc.song.Version,
+	}
+
+	if sc.highlight != nil {
+		targets = append(targets, &sc.highlight.Song, &sc.highlight.Group, &sc.highlight.Text)
+	}
+
+	if sc.match != nil {
+		targets = append(targets, &sc.match.Field, &sc.match.Score)
+	}
+
+	return targets
+}
+
+func (sc *songScanner) result() *models.Song {
+	song := sc.song
+	if song.ReleaseDate == nil && sc.albumReleaseDate != nil {
+		song.ReleaseDate = sc.albumReleaseDate
+		song.ReleaseDateInherited = true
+	}
+
+	song.Highlight = sc.highlight
+	song.Match = sc.match
+	return &song
+}
+
+func scanSong(row pgx.Row) (*models.Song, error) {
+	sc := songScanner{}
+	err := row.Scan(sc.targets()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sc.result(), nil
+}
+
+func collectSongs(rows pgx.Rows) ([]*models.Song, error) {
+	songs := make([]*models.Song, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		song, err := scanSong(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		songs = append(songs, song)
+	}
+
+	return songs, rows.Err()
+}
+
+func (r *PostgresSongRepo) GetWithFilterAndPagination(
 	ctx context.Context,
-	searchQuery string,
+	filter models.SongFilter,
+	sort []models.SongSortKey,
+	count models.SongCount,
 	limit int, offset int,
-) ([]*models.Song, int, error) {
-	var amount int
-	query := `SELECT count(*) as amount FROM song`
-	row := r.db.QueryRow(ctx, query)
-	err := row.Scan(&amount)
+) ([]*models.Song, *int, error) {
+	var (
+		songs []*models.Song
+		total *int
+	)
+
+	err := r.inSnapshot(ctx, func(tx pgx.Tx) error {
+		var err error
+		total, err = countSongs(ctx, tx, filter, count)
+		if err != nil {
+			return err
+		}
+
+		where := songWhere(filter)
+		order, err := songOrder(sort, filter, where)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			SELECT ` + songColumns + songHighlight(filter, where) + songMatch(filter, where) + `
+			FROM ` + songFrom + `
+			` + where.sql() + `
+			` + orderBy(order) + ` LIMIT ` + where.arg(limit) + ` OFFSET ` + where.arg(offset) + `
+		`
+		rows, err := tx.Query(ctx, query, where.args...)
+		if err != nil {
+			return err
+		}
+
+		songs = make([]*models.Song, 0)
+
+		defer rows.Close()
+		for rows.Next() {
+			sc := newListScanner(filter)
+			err = rows.Scan(sc.targets()...)
+			if err != nil {
+				return err
+			}
+
+			songs = append(songs, sc.result())
+		}
+
+		return rows.Err()
+	})
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 
-	var rows pgx.Rows
-	if searchQuery == "" {
-		query = `
-			SELECT id, song, "group", "text", release_date, "link"
-			FROM song
-			ORDER BY created_at ASC LIMIT $1 OFFSET $2
+	return songs, total, nil
+}
+
+// GetWithFilterAndKeyset выбирает limit песен после (или перед) граничной песней keyset, nil - с начала списка
+func (r *PostgresSongRepo) GetWithFilterAndKeyset(
+	ctx context.Context,
+	filter models.SongFilter,
+	sort []models.SongSortKey,
+	keyset *models.SongKeyset,
+	count models.SongCount,
+	limit int,
+) (*models.SongPage, error) {
+	where := songWhere(filter)
+	order, err := songOrder(sort, filter, where)
+	if err != nil {
+		return nil, err
+	}
+
+	backward := keyset != nil && keyset.Backward
+	if backward {
+		// предыдущая страница выбирается в обратном порядке и разворачивается после выборки
+		order = reversed(order)
+	}
+
+	if keyset != nil {
+		if len(keyset.Values) != len(order) {
+			return nil, models.ErrInvalidSongCursor
+		}
+
+		where.and(keysetCondition(order, keyset.Values, where))
+	}
+
+	page := &models.SongPage{
+		Songs: make([]*models.Song, 0),
+	}
+	var keys [][]string
+
+	err = r.inSnapshot(ctx, func(tx pgx.Tx) error {
+		var err error
+		// количество считается по фильтру без условия курсора: это размер всего списка, а не остаток
+		page.Total, err = countSongs(ctx, tx, filter, count)
+		if err != nil {
+			return err
+		}
+
+		// лишняя строка показывает, есть ли песни за страницей
+		query := `
+			SELECT ` + songColumns + songHighlight(filter, where) + songMatch(filter, where) + `, ` + orderValues(order) + `
+			FROM ` + songFrom + `
+			` + where.sql() + `
+			` + orderBy(order) + ` LIMIT ` + where.arg(limit+1) + `
 		`
-		rows, err = r.db.Query(ctx, query, limit, offset)
-	} else {
-		query = `
-			SELECT id, song, "group", "text", release_date, "link"
-			FROM song
-			where to_tsvector(song || ' ' || "group" || ' ' || "text") @@ websearch_to_tsquery($1)
-			ORDER BY created_at ASC LIMIT $2 OFFSET $3
+		rows, err := tx.Query(ctx, query, where.args...)
+		if err != nil {
+			return err
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			sc := newListScanner(filter)
+			values := make([]string, len(order))
+
+			targets := sc.targets()
+			for i := range values {
+				targets = append(targets, &values[i])
+			}
+
+			err = rows.Scan(targets...)
+			if err != nil {
+				return err
+			}
+
+			page.Songs = append(page.Songs, sc.result())
+			keys = append(keys, values)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(page.Songs) > limit {
+		page.HasMore = true
+		page.Songs = page.Songs[:limit]
+		keys = keys[:limit]
+	}
+
+	if backward {
+		slices.Reverse(page.Songs)
+		slices.Reverse(keys)
+	}
+
+	if len(keys) > 0 {
+		page.First = keys[0]
+		page.Last = keys[len(keys)-1]
+	}
+
+	return page, nil
+}
+
+// GetSuggestions выбирает limit песен, название или исполнитель которых начинаются с input или похожи на него.
+// Совпадения по началу строки идут первыми.
+func (r *PostgresSongRepo) GetSuggestions(ctx context.Context, input string, limit int) ([]*models.SongSuggestion, error) {
+	songSimilarity, groupSimilarity := wordSimilarity(`s.song`, `$1`), wordSimilarity(`s."group"`, `$1`)
+	query := `
+		SELECT s.id, s.song, s."group"` + matchColumns(songSimilarity, groupSimilarity) + `
+		FROM song s
+		WHERE s.deleted_at IS NULL
+			AND (normalize_name($1) <% normalize_name(s.song) OR normalize_name($1) <% normalize_name(s."group"))
+		ORDER BY
+			starts_with(normalize_name(s.song), normalize_name($1))
+				OR starts_with(normalize_name(s."group"), normalize_name($1)) DESC,
+			greatest(` + songSimilarity + `, ` + groupSimilarity + `) DESC,
+			s.id
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, input, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]*models.SongSuggestion, 0, limit)
+
+	defer rows.Close()
+	for rows.Next() {
+		suggestion := &models.SongSuggestion{}
+		err = rows.Scan(&suggestion.Id, &suggestion.Song, &suggestion.Group, &suggestion.Match.Field, &suggestion.Match.Score)
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, rows.Err()
+}
+
+// SearchLyrics ищет запрос в каждом куплете отдельно и возвращает песни с номерами совпавших куплетов.
+// Номера куплетов - их позиции, как в пагинации по куплетам. Песни упорядочены по лучшему совпадению куплета.
+func (r *PostgresSongRepo) SearchLyrics(
+	ctx context.Context,
+	query models.LyricsQuery,
+	limit int, offset int,
+) ([]*models.LyricsMatch, int, error) {
+	w := &whereBuilder{}
+	text := w.arg(query.Text)
+
+	// с языком поиска запрос разбирается его конфигурацией: to_tsquery(config, text)
+	parsed, config := text, ""
+	if query.Lang != "" {
+		config = w.arg(searchConfig(query.Lang)) + `::regconfig`
+		parsed = config + `, ` + text
+	}
+
+	tsquery := `websearch_to_tsquery(` + parsed + `)`
+	verseCond := `v.vector @@ q.query`
+	if query.Distance > 0 {
+		tsquery = `plainto_tsquery(` + parsed + `)`
+		verseCond += ` AND ` + withinDistance(`v.vector`, parsed, w.arg(query.Distance))
+	}
+
+	// песни сначала отбираются по индексу, затем совпадение проверяется по каждому куплету
+	verses := `
+		SELECT s.id, s.song, s."group", verse.position, to_tsvector(s.search_config, verse."text") AS vector, ''::text AS lang
+		FROM song s JOIN verse ON verse.song_id = s.id, q
+		WHERE s.deleted_at IS NULL AND ` + songDocument + ` @@ q.query
+	`
+	if query.Lang != "" {
+		// ищутся варианты текста на языке поиска, а у песен без такого варианта - оригиналы,
+		// проиндексированные той же конфигурацией. Куплеты варианта - блоки текста между пустыми строками
+		lang := langMatch(`lv.lang`, w.arg(query.Lang))
+		verses = `
+			SELECT s.id, s.song, s."group", verse.position, to_tsvector(s.search_config, verse."text") AS vector, ''::text AS lang
+			FROM song s JOIN verse ON verse.song_id = s.id, q
+			WHERE s.deleted_at IS NULL AND s.search_config = ` + config + ` AND ` + songDocument + ` @@ q.query
+				AND NOT EXISTS (SELECT 1 FROM lyrics_variant lv WHERE lv.song_id = s.id AND ` + lang + `)
+			UNION ALL
+			SELECT s.id, s.song, s."group", p.position::int, to_tsvector(lv.search_config, p."text"), lv.lang
+			FROM lyrics_variant lv JOIN song s ON s.id = lv.song_id, q,
+				regexp_split_to_table(lv."text", '\n\n') WITH ORDINALITY AS p("text", position)
+			WHERE s.deleted_at IS NULL AND ` + lang + ` AND lv.search_vector @@ q.query
 		`
-		rows, err = r.db.Query(ctx, query, searchQuery, limit, offset)
 	}
 
+	matches := `
+		WITH q AS (SELECT ` + tsquery + ` AS query),
+		verses AS (` + verses + `),
+		matches AS (
+			SELECT v.id, v.song, v."group", v.lang, array_agg(v.position ORDER BY v.position) AS verses, max(ts_rank_cd(v.vector, q.query)) AS rank
+			FROM verses v, q
+			WHERE ` + verseCond + `
+			GROUP BY v.id, v.song, v."group", v.lang
+		)
+	`
+
+	var (
+		amount int
+		found  []*models.LyricsMatch
+	)
+
+	err := r.inSnapshot(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, matches+`SELECT count(*) FROM matches`, w.args...).Scan(&amount)
+		if err != nil {
+			return err
+		}
+
+		query := matches + `
+			SELECT id, song, "group", verses, lang FROM matches
+			ORDER BY rank DESC, id, lang LIMIT ` + w.arg(limit) + ` OFFSET ` + w.arg(offset)
+		rows, err := tx.Query(ctx, query, w.args...)
+		if err != nil {
+			return err
+		}
+
+		found = make([]*models.LyricsMatch, 0)
+
+		defer rows.Close()
+		for rows.Next() {
+			match := &models.LyricsMatch{}
+			err = rows.Scan(&match.Id, &match.Song, &match.Group, &match.Verses, &match.Lang)
+			if err != nil {
+				return err
+			}
+
+			found = append(found, match)
+		}
+
+		return rows.Err()
+	})
 	if err != nil {
 		return nil, 0, err
 	}
 
-	songs := make([]*models.Song, 0)
+	return found, amount, nil
+}
+
+// SyncSearchConfig переиндексирует песни, проиндексированные не с текущей конфигурацией поиска соединения,
+// например после смены SEARCH_LANGUAGE. Поисковый вектор пересчитывается postgres при обновлении search_config.
+func (r *PostgresSongRepo) SyncSearchConfig(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE song SET search_config = current_setting('default_text_search_config')::regconfig
+		WHERE search_config <> current_setting('default_text_search_config')::regconfig
+	`
+	tag, err := r.db.Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// inSnapshot выполняет чтения в одной read-only транзакции REPEATABLE READ,
+// чтобы количество песен и сама страница были согласованы между собой
+func (r *PostgresSongRepo) inSnapshot(ctx context.Context, read func(tx pgx.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	err = read(tx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// countSongs считает песни, подходящие под фильтр: точно, по оценке планировщика или не считает вовсе
+func countSongs(ctx context.Context, tx pgx.Tx, filter models.SongFilter, count models.SongCount) (*int, error) {
+	where := songWhere(filter)
+
+	var amount int
+	switch count {
+	case models.CountNone:
+		return nil, nil
+	case models.CountEstimate:
+		var plan []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+
+		query := `EXPLAIN (FORMAT JSON) SELECT 1 FROM ` + songFrom + ` ` + where.sql()
+		err := tx.QueryRow(ctx, query, where.args...).Scan(&plan)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(plan) == 0 {
+			return nil, errors.New("empty query plan")
+		}
+
+		amount = int(plan[0].Plan.Rows)
+	default:
+		query := `SELECT count(*) FROM ` + songFrom + ` ` + where.sql()
+		err := tx.QueryRow(ctx, query, where.args...).Scan(&amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &amount, nil
+}
+
+// GetFacets считает, сколько найденных по тем же условиям песен относится к каждому жанру и тегу
+func (r *PostgresSongRepo) GetFacets(ctx context.Context, filter models.SongFilter) (*models.SongFacets, error) {
+	where := songWhere(filter)
+	matched := `SELECT s.id FROM ` + songFrom + ` ` + where.sql()
+
+	genres, err := r.countFacets(ctx, `
+		SELECT g.name, count(*) AS amount
+		FROM song_genre sg JOIN genre g ON g.id = sg.genre_id
+		WHERE sg.song_id IN (`+matched+`)
+		GROUP BY g.id, g.name
+		ORDER BY amount DESC, g.name ASC
+	`, where.args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count genre facets: %w", err)
+	}
+
+	tags, err := r.countFacets(ctx, `
+		SELECT t.name, count(*) AS amount
+		FROM song_tag st JOIN tag t ON t.id = st.tag_id
+		WHERE st.song_id IN (`+matched+`)
+		GROUP BY t.id, t.name
+		ORDER BY amount DESC, t.name ASC
+	`, where.args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tag facets: %w", err)
+	}
+
+	return &models.SongFacets{
+		Genres: genres,
+		Tags:   tags,
+	}, nil
+}
+
+func (r *PostgresSongRepo) countFacets(ctx context.Context, query string, args []any) ([]models.FacetCount, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := make([]models.FacetCount, 0)
 
 	defer rows.Close()
 	for rows.Next() {
-		song := models.Song{}
-		err = rows.Scan(&song.Id, &song.Song, &song.Group, &song.Text, &song.ReleaseDate, &song.Link)
+		facet := models.FacetCount{}
+		err = rows.Scan(&facet.Name, &facet.Count)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 
-		songs = append(songs, &song)
+		facets = append(facets, facet)
+	}
+
+	return facets, rows.Err()
+}
+
+func (r *PostgresSongRepo) GetByArtistIdWithPagination(
+	ctx context.Context,
+	artistId int64,
+	limit int, offset int,
+) ([]*models.Song, int, error) {
+	var amount int
+	query := `SELECT count(*) as amount FROM song WHERE artist_id = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRow(ctx, query, artistId).Scan(&amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query = `
+		SELECT ` + songColumns + `
+		FROM ` + songFrom + `
+		WHERE s.artist_id = $1
+		ORDER BY ` + songReleaseDate + ` ASC NULLS LAST, s.created_at ASC LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(ctx, query, artistId, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	songs, err := collectSongs(rows)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return songs, amount, nil
 }
 
 func (r *PostgresSongRepo) GetById(ctx context.Context, id int64) (*models.Song, error) {
-	song := models.Song{}
-
 	query := `
-		SELECT id, song, "group", "text", release_date, "link" FROM song 
-		WHERE id = $1
+		SELECT ` + songColumns + ` FROM ` + songFrom + `
+		WHERE s.id = $1
 	`
 
-	row := r.db.QueryRow(ctx, query, id)
-	err := row.Scan(&song.Id, &song.Song, &song.Group, &song.Text, &song.ReleaseDate, &song.Link)
+	song, err := scanSong(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrSongNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &song, nil
+	return song, nil
 }
 
 func (r *PostgresSongRepo) Add(ctx context.Context, song *models.Song) error {
@@ -100,41 +628,71 @@ func (r *PostgresSongRepo) Add(ctx context.Context, song *models.Song) error {
 		}
 	}()
 
+	song.ArtistId, song.Group, err = upsertArtist(ctx, tx, song.Group)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO song ("group", song, "text", "link", release_date)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO song ("group", artist_id, song, "text", "link", release_date, enrichment_status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, version
 	`
-	_, err = tx.Exec(ctx, query, song.Group, song.Song, song.Text, song.Link, song.ReleaseDate)
+	err = tx.QueryRow(
+		ctx,
+		query,
+		song.Group,
+		song.ArtistId,
+		song.Song,
+		song.Text,
+		song.Link,
+		song.ReleaseDate,
+		song.EnrichmentStatus,
+	).Scan(&song.Id, &song.Version)
+	if isSongTitleTaken(err) {
+		err = songExistsError(ctx, r.db, song.ArtistId, song.Song)
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to add song: %w", err)
 	}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if song.Verses != nil {
+		err = replaceVerses(ctx, tx, song.Id, song.Verses)
+		if err != nil {
+			return err
+		}
 	}
 
-	return nil
-}
-
-func (r *PostgresSongRepo) Update(ctx context.Context, id int64, song *models.Song) (*models.Song, error) {
-	prevData, err := r.GetById(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find a song to update: %w", err)
+	// задача на обогащение ставится в очередь в той же транзакции, что и сама песня
+	if song.EnrichmentStatus == models.EnrichmentPending {
+		query = `
+			INSERT INTO enrichment_job (song_id)
+			VALUES ($1)
+		`
+		_, err = tx.Exec(ctx, query, song.Id)
+		if err != nil {
+			return fmt.Errorf("failed to enqueue song enrichment: %w", err)
+		}
 	}
 
-	bytes, err := json.Marshal(song)
+	err = recordRevision(ctx, tx, song.Id, models.RevisionCreate, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal song struct: %w", err)
+		return err
 	}
 
-	err = json.Unmarshal(bytes, prevData)
+	err = tx.Commit(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to merge new data to song struct: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	song = prevData
+	return nil
+}
 
+// Update передает сохраненную песню в edit и записывает результат: song, group, text, link, release_date, lang
+// и куплеты, если edit их заполнил. Чтение, правка и запись идут в одной транзакции под блокировкой песни,
+// ifMatch - допустимые версии песни, nil - без проверки версии
+func (r *PostgresSongRepo) Update(ctx context.Context, id int64, edit func(song *models.Song) error, ifMatch []int) (*models.Song, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -148,10 +706,38 @@ func (r *PostgresSongRepo) Update(ctx context.Context, id int64, song *models.So
 		}
 	}()
 
-	query := `
+	err = lockSongIfMatch(ctx, tx, id, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + songColumns + ` FROM ` + songFrom + ` WHERE s.id = $1`
+	song, err := scanSong(tx.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find a song to update: %w", err)
+	}
+
+	// правится собственная дата песни: дата, унаследованная от альбома, в песню не сохраняется
+	if song.ReleaseDateInherited {
+		song.ReleaseDate = nil
+		song.ReleaseDateInherited = false
+	}
+
+	err = edit(song)
+	if err != nil {
+		return nil, err
+	}
+
+	song.ArtistId, song.Group, err = upsertArtist(ctx, tx, song.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
 		UPDATE song
-		SET song = $1, "group" = $2, "link" = $3, "text" = $4, release_date = $5, updated_at = now() 
-		WHERE id = $6;
+		SET song = $1, "group" = $2, artist_id = $3, "link" = $4, "text" = $5, release_date = $6, lang = nullif($7, ''),
+			version = version + 1, updated_at = now()
+		WHERE id = $8;
 	`
 
 	_, err = tx.Exec(
@@ -159,24 +745,44 @@ func (r *PostgresSongRepo) Update(ctx context.Context, id int64, song *models.So
 		query,
 		song.Song,
 		song.Group,
+		song.ArtistId,
 		song.Link,
 		song.Text,
 		song.ReleaseDate,
+		song.Lang,
 		song.Id,
 	)
+	if isSongTitleTaken(err) {
+		err = songExistsError(ctx, r.db, song.ArtistId, song.Song)
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to update song: %w", err)
 	}
 
+	if song.Verses != nil {
+		err = replaceVerses(ctx, tx, song.Id, song.Verses)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = recordRevision(ctx, tx, song.Id, models.RevisionUpdate, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return song, nil
+	return r.GetById(ctx, song.Id)
 }
 
-func (r *PostgresSongRepo) Delete(ctx context.Context, id int64) error {
+// Delete переносит песню в корзину. Песня в корзине не видна в выборках и не изменяется до восстановления.
+// ifMatch - допустимые версии песни, nil - без проверки версии
+func (r *PostgresSongRepo) Delete(ctx context.Context, id int64, ifMatch []int) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -190,8 +796,19 @@ func (r *PostgresSongRepo) Delete(ctx context.Context, id int64) error {
 		}
 	}()
 
+	err = lockSongIfMatch(ctx, tx, id, ifMatch)
+	if err != nil {
+		return err
+	}
+
+	// снимок удаляемой песни остается в истории
+	err = recordRevision(ctx, tx, id, models.RevisionDelete, nil)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		DELETE FROM song WHERE id = $1
+		UPDATE song SET deleted_at = now(), version = version + 1 WHERE id = $1
 	`
 	_, err = tx.Exec(ctx, query, id)
 	if err != nil {
@@ -205,3 +822,216 @@ func (r *PostgresSongRepo) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// purgeBatchSize ограничивает количество песен, удаляемых из корзины одним запросом
+const purgeBatchSize = 500
+
+func (r *PostgresSongRepo) GetTrashWithPagination(ctx context.Context, limit int, offset int) ([]*models.Song, int, error) {
+	var (
+		amount int
+		songs  []*models.Song
+	)
+
+	err := r.inSnapshot(ctx, func(tx pgx.Tx) error {
+		query := `SELECT count(*) as amount FROM song WHERE deleted_at IS NOT NULL`
+		err := tx.QueryRow(ctx, query).Scan(&amount)
+		if err != nil {
+			return err
+		}
+
+		query = `
+			SELECT ` + songColumns + `, s.deleted_at
+			FROM ` + trashFrom + `
+			WHERE s.deleted_at IS NOT NULL
+			ORDER BY s.deleted_at DESC, s.id DESC LIMIT $1 OFFSET $2
+		`
+		rows, err := tx.Query(ctx, query, limit, offset)
+		if err != nil {
+			return err
+		}
+
+		songs = make([]*models.Song, 0)
+
+		defer rows.Close()
+		for rows.Next() {
+			sc := songScanner{}
+			err = rows.Scan(append(sc.targets(), &sc.song.DeletedAt)...)
+			if err != nil {
+				return err
+			}
+
+			songs = append(songs, sc.result())
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return songs, amount, nil
+}
+
+// RestoreFromTrash возвращает песню из корзины и записывает ревизию restore без restored_from
+func (r *PostgresSongRepo) RestoreFromTrash(ctx context.Context, id int64) (*models.Song, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	var (
+		artistId int64
+		title    string
+	)
+
+	query := `
+		UPDATE song SET deleted_at = NULL, version = version + 1, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING artist_id, song
+	`
+	err = tx.QueryRow(ctx, query, id).Scan(&artistId, &title)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = models.ErrSongNotInTrash
+		return nil, err
+	}
+	// пока песня была в корзине, у исполнителя появилась песня с тем же названием
+	if isSongTitleTaken(err) {
+		err = r.trashedSongExistsError(ctx, id)
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore song from trash: %w", err)
+	}
+
+	err = recordRevision(ctx, tx, id, models.RevisionRestore, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.GetById(ctx, id)
+}
+
+// trashedSongExistsError возвращает ошибку дубля для песни в корзине, которую не удалось восстановить
+func (r *PostgresSongRepo) trashedSongExistsError(ctx context.Context, id int64) error {
+	var (
+		artistId int64
+		title    string
+	)
+
+	query := `SELECT artist_id, song FROM song WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(&artistId, &title)
+	if err != nil {
+		return models.ErrSongAlreadyExists
+	}
+
+	return songExistsError(ctx, r.db, artistId, title)
+}
+
+// PurgeTrash окончательно удаляет песни, попавшие в корзину раньше before, и возвращает их количество.
+// Песни удаляются пачками, чтобы не держать долгие блокировки
+func (r *PostgresSongRepo) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM song
+		WHERE id IN (
+			SELECT id FROM song
+			WHERE deleted_at < $1
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	var purged int64
+	for {
+		tag, err := r.db.Exec(ctx, query, before, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge trash: %w", err)
+		}
+
+		purged += tag.RowsAffected()
+		if tag.RowsAffected() < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// songTitleKey - уникальный индекс названий песен исполнителя без учета регистра и пробелов, песни в корзине не учитываются
+const songTitleKey = "song_artist_title_key"
+
+func isSongTitleTaken(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == songTitleKey
+}
+
+// songExistsError возвращает ошибку дубля с id песни исполнителя с тем же названием. Вызывается после нарушения
+// songTitleKey, когда транзакция записи уже прервана, поэтому песня ищется вне ее
+func songExistsError(ctx context.Context, db *pgxpool.Pool, artistId int64, title string) error {
+	var id int64
+	query := `
+		SELECT id FROM song
+		WHERE artist_id = $1 AND normalize_name(song) = normalize_name($2) AND deleted_at IS NULL
+	`
+	err := db.QueryRow(ctx, query, artistId, title).Scan(&id)
+	if err != nil {
+		return models.ErrSongAlreadyExists
+	}
+
+	return &models.SongExistsError{SongId: id}
+}
+
+// lockSong блокирует строку песни до конца транзакции, чтобы конкурентные правки связей песни шли по очереди.
+// Песня в корзине считается ненайденной
+func lockSong(ctx context.Context, tx pgx.Tx, id int64) error {
+	query := `SELECT id FROM song WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err := tx.QueryRow(ctx, query, id).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrSongNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock song: %w", err)
+	}
+
+	return nil
+}
+
+// lockSongIfMatch блокирует песню, как lockSong, и проверяет, что ее версия - одна из ifMatch
+func lockSongIfMatch(ctx context.Context, tx pgx.Tx, id int64, ifMatch []int) error {
+	var version int
+	query := `SELECT version FROM song WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err := tx.QueryRow(ctx, query, id).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrSongNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock song: %w", err)
+	}
+
+	if ifMatch != nil && !slices.Contains(ifMatch, version) {
+		return fmt.Errorf("song version %d: %w", version, models.ErrSongVersionMismatch)
+	}
+
+	return nil
+}
+
+// bumpSongVersion увеличивает версию песни при правке ее связей, не меняющей саму строку песни
+func bumpSongVersion(ctx context.Context, tx pgx.Tx, id int64) error {
+	query := `UPDATE song SET version = version + 1, updated_at = now() WHERE id = $1`
+	_, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to bump song version: %w", err)
+	}
+
+	return nil
+}
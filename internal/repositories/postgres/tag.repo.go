@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type PostgresTagRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTagRepo(db *pgxpool.Pool) *PostgresTagRepo {
+	return &PostgresTagRepo{db: db}
+}
+
+func (r *PostgresTagRepo) GetWithPagination(ctx context.Context, limit int, offset int) ([]*models.Tag, int, error) {
+	var amount int
+	query := `SELECT count(*) as amount FROM tag`
+	err := r.db.QueryRow(ctx, query).Scan(&amount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query = `
+		SELECT t.id, t.name, (
+			SELECT count(*) FROM song_tag st JOIN song s ON s.id = st.song_id
+			WHERE st.tag_id = t.id AND s.deleted_at IS NULL
+		)
+		FROM tag t
+		ORDER BY t.normalized_name ASC, t.id ASC LIMIT $1 OFFSET $2
+	`
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tags := make([]*models.Tag, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		tag := models.Tag{}
+		err = rows.Scan(&tag.Id, &tag.Name, &tag.SongsAmount)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		tags = append(tags, &tag)
+	}
+
+	return tags, amount, rows.Err()
+}
+
+func (r *PostgresTagRepo) Add(ctx context.Context, tag *models.Tag) error {
+	query := `
+		INSERT INTO tag (name, normalized_name)
+		VALUES ($1, normalize_name($1))
+		RETURNING id, name
+	`
+
+	err := r.db.QueryRow(ctx, query, cleanName(tag.Name)).Scan(&tag.Id, &tag.Name)
+	if isPgError(err, pgUniqueViolation) {
+		return models.ErrTagAlreadyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTagRepo) Delete(ctx context.Context, id int64) error {
+	query := `
+		DELETE FROM tag WHERE id = $1
+	`
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresTagRepo) SetSongTags(ctx context.Context, songId int64, names []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	err = lockSong(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	cleaned := make([]string, 0, len(names))
+	for _, name := range names {
+		cleaned = append(cleaned, cleanName(name))
+	}
+
+	// теги свободные: отсутствующие создаются, у существующих сохраняется исходное написание
+	query := `
+		INSERT INTO tag (name, normalized_name)
+		SELECT DISTINCT ON (normalize_name(n)) n, normalize_name(n)
+		FROM unnest($1::text[]) n
+		ON CONFLICT (normalized_name) DO UPDATE SET normalized_name = EXCLUDED.normalized_name
+		RETURNING id
+	`
+	rows, err := tx.Query(ctx, query, cleaned)
+	if err != nil {
+		return fmt.Errorf("failed to resolve tags: %w", err)
+	}
+
+	tagIds, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return fmt.Errorf("failed to resolve tags: %w", err)
+	}
+
+	query = `DELETE FROM song_tag WHERE song_id = $1`
+	_, err = tx.Exec(ctx, query, songId)
+	if err != nil {
+		return fmt.Errorf("failed to clear song tags: %w", err)
+	}
+
+	query = `
+		INSERT INTO song_tag (song_id, tag_id)
+		SELECT $1, unnest($2::bigint[])
+	`
+	_, err = tx.Exec(ctx, query, songId, tagIds)
+	if err != nil {
+		return fmt.Errorf("failed to set song tags: %w", err)
+	}
+
+	err = bumpSongVersion(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,323 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+// verseSeparator - разделитель куплетов в тексте песни, который собирается из таблицы verse
+const verseSeparator = "\n\n"
+
+type PostgresVerseRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresVerseRepo(db *pgxpool.Pool) *PostgresVerseRepo {
+	return &PostgresVerseRepo{db: db}
+}
+
+func (r *PostgresVerseRepo) GetBySongId(ctx context.Context, songId int64) ([]*models.Verse, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM song WHERE id = $1 AND deleted_at IS NULL)`
+	err := r.db.QueryRow(ctx, query, songId).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, models.ErrSongNotFound
+	}
+
+	query = `
+		SELECT id, position, kind, "text"
+		FROM verse
+		WHERE song_id = $1
+		ORDER BY position
+	`
+	rows, err := r.db.Query(ctx, query, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	verses := make([]*models.Verse, 0)
+
+	defer rows.Close()
+	for rows.Next() {
+		verse := &models.Verse{}
+		err = rows.Scan(&verse.Id, &verse.Position, &verse.Kind, &verse.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		verses = append(verses, verse)
+	}
+
+	return verses, rows.Err()
+}
+
+func (r *PostgresVerseRepo) GetById(ctx context.Context, songId int64, id int64) (*models.Verse, error) {
+	query := `
+		SELECT id, position, kind, "text"
+		FROM verse
+		WHERE id = $1 AND song_id = $2
+	`
+
+	verse := &models.Verse{}
+	err := r.db.QueryRow(ctx, query, id, songId).Scan(&verse.Id, &verse.Position, &verse.Kind, &verse.Text)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrVerseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return verse, nil
+}
+
+func (r *PostgresVerseRepo) Insert(ctx context.Context, songId int64, verse *models.Verse) error {
+	return r.editVerses(ctx, songId, func(tx pgx.Tx, amount int) error {
+		if verse.Position <= 0 || verse.Position > amount+1 {
+			verse.Position = amount + 1
+		}
+
+		query := `
+			UPDATE verse
+			SET position = position + 1
+			WHERE song_id = $1 AND position >= $2
+		`
+		_, err := tx.Exec(ctx, query, songId, verse.Position)
+		if err != nil {
+			return fmt.Errorf("failed to shift verses: %w", err)
+		}
+
+		query = `
+			INSERT INTO verse (song_id, position, kind, "text")
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`
+		err = tx.QueryRow(ctx, query, songId, verse.Position, verse.Kind, verse.Text).Scan(&verse.Id)
+		if err != nil {
+			return fmt.Errorf("failed to add verse: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *PostgresVerseRepo) Update(ctx context.Context, songId int64, id int64, verse *models.Verse) (*models.Verse, error) {
+	err := r.editVerses(ctx, songId, func(tx pgx.Tx, _ int) error {
+		query := `
+			UPDATE verse
+			SET kind = $1, "text" = $2
+			WHERE id = $3 AND song_id = $4
+		`
+		tag, err := tx.Exec(ctx, query, verse.Kind, verse.Text, id, songId)
+		if err != nil {
+			return fmt.Errorf("failed to update verse: %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return models.ErrVerseNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetById(ctx, songId, id)
+}
+
+func (r *PostgresVerseRepo) Move(ctx context.Context, songId int64, id int64, position int) error {
+	return r.editVerses(ctx, songId, func(tx pgx.Tx, amount int) error {
+		var current int
+		query := `SELECT position FROM verse WHERE id = $1 AND song_id = $2`
+		err := tx.QueryRow(ctx, query, id, songId).Scan(&current)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrVerseNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		position = min(max(position, 1), amount)
+		if position == current {
+			return nil
+		}
+
+		if position < current {
+			query = `
+				UPDATE verse
+				SET position = position + 1
+				WHERE song_id = $1 AND position >= $2 AND position < $3
+			`
+		} else {
+			query = `
+				UPDATE verse
+				SET position = position - 1
+				WHERE song_id = $1 AND position > $3 AND position <= $2
+			`
+		}
+
+		_, err = tx.Exec(ctx, query, songId, position, current)
+		if err != nil {
+			return fmt.Errorf("failed to shift verses: %w", err)
+		}
+
+		query = `UPDATE verse SET position = $1 WHERE id = $2`
+		_, err = tx.Exec(ctx, query, position, id)
+		if err != nil {
+			return fmt.Errorf("failed to move verse: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *PostgresVerseRepo) Delete(ctx context.Context, songId int64, id int64) error {
+	return r.editVerses(ctx, songId, func(tx pgx.Tx, _ int) error {
+		query := `DELETE FROM verse WHERE id = $1 AND song_id = $2`
+		tag, err := tx.Exec(ctx, query, id, songId)
+		if err != nil {
+			return fmt.Errorf("failed to delete verse: %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return models.ErrVerseNotFound
+		}
+
+		return nil
+	})
+}
+
+// editVerses выполняет изменение куплетов в транзакции под блокировкой строки песни.
+// После изменения позиции перенумеровываются подряд с 1, а текст песни собирается из куплетов заново.
+func (r *PostgresVerseRepo) editVerses(ctx context.Context, songId int64, edit func(tx pgx.Tx, amount int) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil || err != nil {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				return
+			}
+		}
+	}()
+
+	err = lockSong(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `SET CONSTRAINTS verse_position_key DEFERRED`)
+	if err != nil {
+		return fmt.Errorf("failed to defer verse constraints: %w", err)
+	}
+
+	var amount int
+	query := `SELECT count(*) FROM verse WHERE song_id = $1`
+	err = tx.QueryRow(ctx, query, songId).Scan(&amount)
+	if err != nil {
+		return err
+	}
+
+	err = edit(tx, amount)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		UPDATE verse v
+		SET position = n.position
+		FROM (
+			SELECT id, row_number() OVER (ORDER BY position ASC, id ASC) AS position
+			FROM verse
+			WHERE song_id = $1
+		) n
+		WHERE v.id = n.id AND v.position <> n.position
+	`
+	_, err = tx.Exec(ctx, query, songId)
+	if err != nil {
+		return fmt.Errorf("failed to renumber verses: %w", err)
+	}
+
+	err = renderSongText(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	err = bumpSongVersion(ctx, tx, songId)
+	if err != nil {
+		return err
+	}
+
+	err = recordRevision(ctx, tx, songId, models.RevisionUpdate, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// replaceVerses заменяет все куплеты песни и собирает из них текст песни
+func replaceVerses(ctx context.Context, tx pgx.Tx, songId int64, verses []*models.Verse) error {
+	_, err := tx.Exec(ctx, `DELETE FROM verse WHERE song_id = $1`, songId)
+	if err != nil {
+		return fmt.Errorf("failed to delete verses: %w", err)
+	}
+
+	kinds := make([]string, 0, len(verses))
+	texts := make([]string, 0, len(verses))
+	for _, verse := range verses {
+		kinds = append(kinds, string(verse.Kind))
+		texts = append(texts, verse.Text)
+	}
+
+	query := `
+		INSERT INTO verse (song_id, position, kind, "text")
+		SELECT $1, v.position, v.kind, v."text"
+		FROM unnest($2::text[], $3::text[]) WITH ORDINALITY AS v(kind, "text", position)
+	`
+	_, err = tx.Exec(ctx, query, songId, kinds, texts)
+	if err != nil {
+		return fmt.Errorf("failed to add verses: %w", err)
+	}
+
+	return renderSongText(ctx, tx, songId)
+}
+
+// renderSongText собирает текст песни из куплетов, текст нужен для полнотекстового поиска по песне.
+// Синхронизированные строки описывают прежний текст, поэтому удаляются. Версию песни увеличивает вызывающая правка
+func renderSongText(ctx context.Context, tx pgx.Tx, songId int64) error {
+	_, err := tx.Exec(ctx, `DELETE FROM lyrics_line WHERE song_id = $1`, songId)
+	if err != nil {
+		return fmt.Errorf("failed to delete lyrics lines: %w", err)
+	}
+
+	query := `
+		UPDATE song
+		SET "text" = coalesce((
+			SELECT string_agg("text", $2 ORDER BY position) FROM verse WHERE song_id = $1
+		), ''), updated_at = now()
+		WHERE id = $1
+	`
+	_, err = tx.Exec(ctx, query, songId, verseSeparator)
+	if err != nil {
+		return fmt.Errorf("failed to render song text: %w", err)
+	}
+
+	return nil
+}
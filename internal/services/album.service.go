@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+const defaultDiscNumber = 1
+
+type AlbumsWithPagination struct {
+	Result      []*models.Album `json:"result"`
+	CurrentPage int             `json:"current_page"`
+	PagesAmount int             `json:"pages_amount"`
+}
+
+// AlbumPatch содержит изменяемые поля альбома, nil - поле не меняется
+type AlbumPatch struct {
+	Title       *string    `json:"title"`
+	Artist      *string    `json:"artist"`
+	ReleaseDate *time.Time `json:"release_date"`
+	CoverLink   *string    `json:"cover_link"`
+}
+
+type AlbumService struct {
+	repo models.AlbumRepository
+}
+
+func NewAlbumService(ar models.AlbumRepository) *AlbumService {
+	return &AlbumService{
+		repo: ar,
+	}
+}
+
+func (as *AlbumService) AddAlbum(ctx context.Context, album *models.Album) error {
+	err := as.repo.Add(ctx, album)
+	if err != nil {
+		return fmt.Errorf("database error while creating an album: %w", err)
+	}
+
+	return nil
+}
+
+func (as *AlbumService) GetAllAlbumsWithPagination(ctx context.Context, limit int, page int) (*AlbumsWithPagination, error) {
+	offset := (page * limit) - limit
+	instances, count, err := as.repo.GetWithPagination(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AlbumsWithPagination{
+		Result:      instances,
+		PagesAmount: pagesAmount(count, limit),
+		CurrentPage: page,
+	}, nil
+}
+
+func (as *AlbumService) GetAlbumById(ctx context.Context, id int64) (*models.Album, error) {
+	return as.repo.GetById(ctx, id)
+}
+
+func (as *AlbumService) UpdateAlbum(ctx context.Context, id int64, patch AlbumPatch) (*models.Album, error) {
+	album, err := as.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Title != nil {
+		album.Title = *patch.Title
+	}
+
+	if patch.Artist != nil {
+		album.Artist = *patch.Artist
+	}
+
+	if patch.ReleaseDate != nil {
+		album.ReleaseDate = patch.ReleaseDate
+	}
+
+	if patch.CoverLink != nil {
+		album.CoverLink = *patch.CoverLink
+	}
+
+	return as.repo.Update(ctx, id, album)
+}
+
+func (as *AlbumService) DeleteAlbum(ctx context.Context, id int64) error {
+	return as.repo.Delete(ctx, id)
+}
+
+func (as *AlbumService) SetAlbumTrack(ctx context.Context, albumId int64, songId int64, discNumber int, trackNumber int) (*models.Album, error) {
+	if discNumber == 0 {
+		discNumber = defaultDiscNumber
+	}
+
+	err := as.repo.SetTrack(ctx, albumId, songId, discNumber, trackNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.repo.GetById(ctx, albumId)
+}
+
+func (as *AlbumService) RemoveAlbumTrack(ctx context.Context, albumId int64, songId int64) error {
+	return as.repo.RemoveTrack(ctx, albumId, songId)
+}
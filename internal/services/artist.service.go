@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type ArtistsWithPagination struct {
+	Result      []*models.Artist `json:"result"`
+	CurrentPage int              `json:"current_page"`
+	PagesAmount int              `json:"pages_amount"`
+}
+
+type ArtistService struct {
+	repo     models.ArtistRepository
+	songRepo models.SongRepository
+}
+
+func NewArtistService(ar models.ArtistRepository, sr models.SongRepository) *ArtistService {
+	return &ArtistService{
+		repo:     ar,
+		songRepo: sr,
+	}
+}
+
+func (as *ArtistService) AddArtist(ctx context.Context, artist *models.Artist) error {
+	err := as.repo.Add(ctx, artist)
+	if err != nil {
+		return fmt.Errorf("database error while creating an artist: %w", err)
+	}
+
+	return nil
+}
+
+func (as *ArtistService) GetAllArtistsWithPagination(ctx context.Context, limit int, page int) (*ArtistsWithPagination, error) {
+	offset := (page * limit) - limit
+	instances, count, err := as.repo.GetWithPagination(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArtistsWithPagination{
+		Result:      instances,
+		PagesAmount: pagesAmount(count, limit),
+		CurrentPage: page,
+	}, nil
+}
+
+func (as *ArtistService) GetArtistById(ctx context.Context, id int64) (*models.Artist, error) {
+	return as.repo.GetById(ctx, id)
+}
+
+func (as *ArtistService) GetArtistSongsWithPagination(ctx context.Context, id int64, limit int, page int) (*SongsWithPagination, error) {
+	_, err := as.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page * limit) - limit
+	instances, count, err := as.songRepo.GetByArtistIdWithPagination(ctx, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SongsWithPagination{
+		Result:      instances,
+		PagesAmount: pagesAmount(count, limit),
+		CurrentPage: page,
+	}, nil
+}
+
+func (as *ArtistService) UpdateArtist(ctx context.Context, id int64, artist models.Artist) (*models.Artist, error) {
+	return as.repo.Update(ctx, id, &artist)
+}
+
+func (as *ArtistService) DeleteArtist(ctx context.Context, id int64) error {
+	return as.repo.Delete(ctx, id)
+}
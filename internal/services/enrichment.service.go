@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+	log "github.com/sirupsen/logrus"
+)
+
+type EnrichmentConfig struct {
+	Workers        int
+	PollInterval   time.Duration
+	LockTimeout    time.Duration
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+type SongEnrichment struct {
+	SongId int64                   `json:"song_id"`
+	Status models.EnrichmentStatus `json:"status"`
+	Job    *models.EnrichmentJob   `json:"job,omitempty"`
+}
+
+type EnrichmentService struct {
+	jobs                  models.EnrichmentJobRepository
+	songs                 models.SongRepository
+	songDetailsApiService SongDetailsApiService
+	config                EnrichmentConfig
+}
+
+func NewEnrichmentService(
+	jobs models.EnrichmentJobRepository,
+	songs models.SongRepository,
+	songDetailsApiService SongDetailsApiService,
+	config EnrichmentConfig,
+) *EnrichmentService {
+	return &EnrichmentService{
+		jobs:                  jobs,
+		songs:                 songs,
+		songDetailsApiService: songDetailsApiService,
+		config:                config,
+	}
+}
+
+func (es *EnrichmentService) GetSongEnrichment(ctx context.Context, songId int64) (*SongEnrichment, error) {
+	song, err := es.songs.GetById(ctx, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	enrichment := &SongEnrichment{
+		SongId: song.Id,
+		Status: song.EnrichmentStatus,
+	}
+
+	job, err := es.jobs.GetLatestBySongId(ctx, songId)
+	if err != nil {
+		return nil, err
+	}
+
+	enrichment.Job = job
+	return enrichment, nil
+}
+
+// StartWorkers запускает пул воркеров, которые работают до отмены ctx.
+// Возвращаемая функция дожидается завершения всех воркеров.
+func (es *EnrichmentService) StartWorkers(ctx context.Context) (wait func()) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < es.config.Workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			es.runWorker(ctx, worker)
+		}(i)
+	}
+
+	log.Infof("Started %d song enrichment workers", es.config.Workers)
+	return wg.Wait
+}
+
+// enrichmentActor - автор ревизий песен, записанных при обогащении
+const enrichmentActor = "enrichment"
+
+func (es *EnrichmentService) runWorker(ctx context.Context, worker int) {
+	// правки воркеров попадают в историю песни от имени обогащения
+	ctx = models.WithActor(ctx, enrichmentActor)
+
+	for {
+		job, err := es.jobs.Claim(ctx, es.config.LockTimeout)
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("enrichment worker %d: %s", worker, err)
+		}
+
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(es.config.PollInterval):
+				continue
+			}
+		}
+
+		es.process(ctx, job)
+	}
+}
+
+func (es *EnrichmentService) process(ctx context.Context, job *models.EnrichmentJob) {
+	err := es.enrich(ctx, job)
+	if err == nil {
+		log.Debugf("song %d enriched (job %d)", job.SongId, job.Id)
+		return
+	}
+
+	if ctx.Err() != nil {
+		// воркер останавливается, задача будет подобрана повторно после LockTimeout
+		return
+	}
+
+	// задачу после LockTimeout забрал другой воркер, он ее и завершит
+	if errors.Is(err, models.ErrEnrichmentJobLost) {
+		log.Debugf("song %d enrichment job %d was claimed by another worker", job.SongId, job.Id)
+		return
+	}
+
+	// песню изменили во время обогащения: задача сразу повторяется с новой версией песни,
+	// такой повтор тоже расходует попытку, иначе постоянно редактируемая песня обогащалась бы бесконечно
+	superseded := errors.Is(err, models.ErrEnrichmentSuperseded)
+	permanent := errors.Is(err, ErrSongDetailsNotFound) || errors.Is(err, ErrSongDetailsBadRequest)
+	if permanent || job.Attempts >= es.config.MaxAttempts {
+		log.Warnf("song %d enrichment failed permanently after %d attempts: %s", job.SongId, job.Attempts, err)
+		if err := es.jobs.Bury(ctx, job, err.Error()); err != nil {
+			logJobUpdateError("bury", job, err)
+		}
+		return
+	}
+
+	runAt := time.Now()
+	if !superseded {
+		runAt = runAt.Add(es.retryDelay(job.Attempts))
+	}
+
+	log.Debugf("song %d enrichment attempt %d failed, retry at %s: %s", job.SongId, job.Attempts, runAt, err)
+	if err := es.jobs.Retry(ctx, job, runAt, err.Error()); err != nil {
+		logJobUpdateError("retry", job, err)
+	}
+}
+
+func logJobUpdateError(action string, job *models.EnrichmentJob, err error) {
+	if errors.Is(err, models.ErrEnrichmentJobLost) {
+		log.Debugf("%s enrichment job %d: %s", action, job.Id, err)
+		return
+	}
+
+	log.Errorf("%s enrichment job %d: %s", action, job.Id, err)
+}
+
+func (es *EnrichmentService) enrich(ctx context.Context, job *models.EnrichmentJob) error {
+	song, err := es.songs.GetById(ctx, job.SongId)
+	if err != nil {
+		return fmt.Errorf("failed to find song to enrich: %w", err)
+	}
+
+	details, err := es.songDetailsApiService.FindSongDetails(ctx, song.Group, song.Song)
+	if err != nil {
+		return err
+	}
+
+	// заполняются только пустые поля: то, что пользователь успел ввести сам, не перезаписывается
+	if song.Text == "" {
+		song.Text = details.Text
+		splitLyrics(song)
+	}
+
+	if song.Link == "" {
+		song.Link = details.Link
+	}
+
+	// дата альбома не сохраняется в песню как собственная
+	if song.ReleaseDateInherited {
+		song.ReleaseDate = nil
+	}
+
+	if song.ReleaseDate == nil && !details.ReleaseDate.IsZero() {
+		song.ReleaseDate = &details.ReleaseDate
+	}
+
+	return es.jobs.Complete(ctx, job, song)
+}
+
+func (es *EnrichmentService) retryDelay(attempt int) time.Duration {
+	delay := es.config.RetryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > es.config.RetryMaxDelay {
+		return es.config.RetryMaxDelay
+	}
+
+	return delay
+}
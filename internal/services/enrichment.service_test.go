@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type fakeEnrichmentJobs struct {
+	models.EnrichmentJobRepository
+
+	completeErr error
+	retriedAt   *time.Time
+	buried      bool
+}
+
+func (f *fakeEnrichmentJobs) Complete(ctx context.Context, job *models.EnrichmentJob, song *models.Song) error {
+	return f.completeErr
+}
+
+func (f *fakeEnrichmentJobs) Retry(ctx context.Context, job *models.EnrichmentJob, runAt time.Time, jobErr string) error {
+	f.retriedAt = &runAt
+	return nil
+}
+
+func (f *fakeEnrichmentJobs) Bury(ctx context.Context, job *models.EnrichmentJob, jobErr string) error {
+	f.buried = true
+	return nil
+}
+
+type fakeEnrichmentSongs struct {
+	models.SongRepository
+}
+
+func (f *fakeEnrichmentSongs) GetById(ctx context.Context, id int64) (*models.Song, error) {
+	return &models.Song{Id: id, Group: "Muse", Song: "Uprising"}, nil
+}
+
+type fakeSongDetails struct {
+	err error
+}
+
+func (f *fakeSongDetails) FindSongDetails(ctx context.Context, group string, song string) (*SongDetails, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &SongDetails{Text: "text"}, nil
+}
+
+func TestEnrichmentProcess(t *testing.T) {
+	const maxAttempts = 3
+
+	tests := []struct {
+		name        string
+		attempts    int
+		detailsErr  error
+		completeErr error
+		wantRetry   bool
+		wantNow     bool
+		wantBury    bool
+	}{
+		{name: "completed", attempts: 1},
+		{name: "api error is retried later", attempts: 1, detailsErr: ErrSongDetailsUnavailable, wantRetry: true},
+		{name: "api error at max attempts is buried", attempts: maxAttempts, detailsErr: ErrSongDetailsUnavailable, wantBury: true},
+		{name: "not found is buried", attempts: 1, detailsErr: ErrSongDetailsNotFound, wantBury: true},
+		{
+			name:        "superseded is retried now",
+			attempts:    1,
+			completeErr: models.ErrEnrichmentSuperseded,
+			wantRetry:   true,
+			wantNow:     true,
+		},
+		{
+			name:        "superseded at max attempts is buried",
+			attempts:    maxAttempts,
+			completeErr: models.ErrEnrichmentSuperseded,
+			wantBury:    true,
+		},
+		{name: "lost job is left to its new worker", attempts: maxAttempts, completeErr: models.ErrEnrichmentJobLost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &fakeEnrichmentJobs{completeErr: tt.completeErr}
+			service := NewEnrichmentService(jobs, &fakeEnrichmentSongs{}, &fakeSongDetails{err: tt.detailsErr}, EnrichmentConfig{
+				MaxAttempts:    maxAttempts,
+				RetryBaseDelay: time.Minute,
+				RetryMaxDelay:  time.Hour,
+			})
+
+			before := time.Now()
+			service.process(context.Background(), &models.EnrichmentJob{Id: 1, SongId: 2, Attempts: tt.attempts})
+
+			if jobs.buried != tt.wantBury {
+				t.Errorf("buried = %t, want %t", jobs.buried, tt.wantBury)
+			}
+
+			if (jobs.retriedAt != nil) != tt.wantRetry {
+				t.Fatalf("retried = %t, want %t", jobs.retriedAt != nil, tt.wantRetry)
+			}
+
+			if tt.wantRetry {
+				now := jobs.retriedAt.Sub(before) < time.Minute
+				if now != tt.wantNow {
+					t.Errorf("retry at %s, want immediate retry %t", jobs.retriedAt, tt.wantNow)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type GenreService struct {
+	repo models.GenreRepository
+}
+
+func NewGenreService(gr models.GenreRepository) *GenreService {
+	return &GenreService{
+		repo: gr,
+	}
+}
+
+func (gs *GenreService) AddGenre(ctx context.Context, genre *models.Genre) error {
+	err := gs.repo.Add(ctx, genre)
+	if err != nil {
+		return fmt.Errorf("database error while creating a genre: %w", err)
+	}
+
+	return nil
+}
+
+// GetGenreTree возвращает корневые жанры, поджанры вложены в Children
+func (gs *GenreService) GetGenreTree(ctx context.Context) ([]*models.Genre, error) {
+	genres, err := gs.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int64]*models.Genre, len(genres))
+	for _, genre := range genres {
+		byId[genre.Id] = genre
+	}
+
+	roots := make([]*models.Genre, 0)
+	for _, genre := range genres {
+		if genre.ParentId == nil {
+			roots = append(roots, genre)
+			continue
+		}
+
+		parent, ok := byId[*genre.ParentId]
+		if !ok {
+			roots = append(roots, genre)
+			continue
+		}
+
+		parent.Children = append(parent.Children, genre)
+	}
+
+	return roots, nil
+}
+
+func (gs *GenreService) GetGenreById(ctx context.Context, id int64) (*models.Genre, error) {
+	return gs.repo.GetById(ctx, id)
+}
+
+func (gs *GenreService) UpdateGenre(ctx context.Context, id int64, genre *models.Genre) (*models.Genre, error) {
+	return gs.repo.Update(ctx, id, genre)
+}
+
+func (gs *GenreService) DeleteGenre(ctx context.Context, id int64) error {
+	return gs.repo.Delete(ctx, id)
+}
+
+func (gs *GenreService) SetSongGenres(ctx context.Context, songId int64, names []string) error {
+	return gs.repo.SetSongGenres(ctx, songId, names)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+	log "github.com/sirupsen/logrus"
+)
+
+type IdempotencyConfig struct {
+	// сколько хранится ответ на запрос с ключом, после этого ключ можно использовать заново
+	KeyTTL time.Duration
+	// через сколько незавершенный запрос считается зависшим и его ключ может занять повтор
+	LockTimeout   time.Duration
+	PurgeInterval time.Duration
+}
+
+type IdempotencyService struct {
+	repo   models.IdempotencyRepository
+	config IdempotencyConfig
+}
+
+func NewIdempotencyService(ir models.IdempotencyRepository, config IdempotencyConfig) *IdempotencyService {
+	return &IdempotencyService{
+		repo:   ir,
+		config: config,
+	}
+}
+
+// Begin занимает ключ за запросом. nil без ошибки - запрос нужно выполнить и затем вызвать Complete или Release,
+// иначе возвращается сохраненный ответ на тот же запрос
+func (is *IdempotencyService) Begin(ctx context.Context, key string, fingerprint string) (*models.IdempotentResponse, error) {
+	now := time.Now()
+	return is.repo.Reserve(ctx, key, fingerprint, now.Add(-is.config.KeyTTL), now.Add(-is.config.LockTimeout))
+}
+
+func (is *IdempotencyService) Complete(ctx context.Context, key string, response *models.IdempotentResponse) error {
+	return is.repo.Complete(ctx, key, response)
+}
+
+func (is *IdempotencyService) Release(ctx context.Context, key string) error {
+	return is.repo.Release(ctx, key)
+}
+
+// StartPurger запускает удаление просроченных ключей раз в PurgeInterval до отмены ctx.
+// Возвращаемая функция дожидается завершения очистки.
+func (is *IdempotencyService) StartPurger(ctx context.Context) (wait func()) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		is.runPurger(ctx)
+	}()
+
+	log.Infof("Started idempotency key purger, key ttl %s", is.config.KeyTTL)
+	return wg.Wait
+}
+
+func (is *IdempotencyService) runPurger(ctx context.Context) {
+	for {
+		purged, err := is.repo.Purge(ctx, time.Now().Add(-is.config.KeyTTL))
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("idempotency key purger: %s", err)
+		}
+
+		if purged > 0 {
+			log.Infof("Purged %d idempotency keys", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(is.config.PurgeInterval):
+		}
+	}
+}
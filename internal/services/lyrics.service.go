@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/lrc"
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrInvalidLrc           = errors.New("invalid lrc file")
+	ErrInvalidLyricsVariant = errors.New("invalid lyrics variant")
+)
+
+type SongLyrics struct {
+	SongId int64 `json:"song_id"`
+	// true, если у песни есть строки с метками времени
+	Synced bool   `json:"synced"`
+	Text   string `json:"text"`
+	// строки синхронизированного текста, пустой список у несинхронизированной песни
+	Lines []*models.LyricsLine `json:"lines"`
+}
+
+type LyricsService struct {
+	songs models.SongRepository
+	repo  models.LyricsRepository
+}
+
+func NewLyricsService(sr models.SongRepository, lr models.LyricsRepository) *LyricsService {
+	return &LyricsService{
+		songs: sr,
+		repo:  lr,
+	}
+}
+
+func (ls *LyricsService) GetLyrics(ctx context.Context, songId int64) (*SongLyrics, error) {
+	lyrics, _, err := ls.getLyrics(ctx, songId)
+	return lyrics, err
+}
+
+// ExportLrc собирает LRC из строк песни, исполнитель и название записываются в теги заголовка
+func (ls *LyricsService) ExportLrc(ctx context.Context, songId int64) (string, error) {
+	lyrics, song, err := ls.getLyrics(ctx, songId)
+	if err != nil {
+		return "", err
+	}
+
+	if !lyrics.Synced {
+		return "", models.ErrSyncedLyricsNotFound
+	}
+
+	lines := make([]lrc.Line, 0, len(lyrics.Lines))
+	for _, line := range lyrics.Lines {
+		lines = append(lines, lrc.Line{
+			Time: time.Duration(line.TimeMs) * time.Millisecond,
+			Text: line.Text,
+		})
+	}
+
+	return lrc.Format(lines, lrc.Tags{Artist: song.Group, Title: song.Song}), nil
+}
+
+// UploadLrc заменяет текст песни синхронизированным. Текст и куплеты собираются из строк LRC:
+// строки без текста разделяют куплеты, поэтому Text песни остается выводимым из строк
+func (ls *LyricsService) UploadLrc(ctx context.Context, songId int64, data string) (*SongLyrics, error) {
+	parsed, err := lrc.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLrc, err)
+	}
+
+	song := models.Song{Text: lrc.Text(parsed)}
+	splitLyrics(&song)
+	if len(song.Verses) == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLrc, lrc.ErrEmpty)
+	}
+
+	lines := make([]*models.LyricsLine, 0, len(parsed))
+	for i, line := range parsed {
+		lines = append(lines, &models.LyricsLine{
+			Position: i + 1,
+			TimeMs:   line.Time.Milliseconds(),
+			Text:     line.Text,
+		})
+	}
+
+	err = ls.repo.ReplaceLines(ctx, songId, lines, song.Verses)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SongLyrics{
+		SongId: songId,
+		Synced: true,
+		Text:   song.Text,
+		Lines:  lines,
+	}, nil
+}
+
+func (ls *LyricsService) GetVariants(ctx context.Context, songId int64) ([]*models.LyricsVariant, error) {
+	return ls.repo.GetVariants(ctx, songId)
+}
+
+// SaveVariant добавляет или заменяет вариант текста на языке variant.Lang. Текст приводится к тому же виду,
+// что и текст песни: куплеты через пустую строку, без разметки [Chorus]
+func (ls *LyricsService) SaveVariant(ctx context.Context, songId int64, variant *models.LyricsVariant) (bool, error) {
+	song := models.Song{Text: variant.Text}
+	splitLyrics(&song)
+	if len(song.Verses) == 0 {
+		return false, ErrInvalidLyricsVariant
+	}
+
+	variant.Text = song.Text
+
+	return ls.repo.SaveVariant(ctx, songId, variant)
+}
+
+func (ls *LyricsService) DeleteVariant(ctx context.Context, songId int64, lang string) error {
+	return ls.repo.DeleteVariant(ctx, songId, lang)
+}
+
+// Localize выбирает текст песни по языкам prefs в порядке предпочтения и возвращает копию песни с этим текстом.
+// Оригинал остается, если он на предпочтительном языке или для языков prefs нет подходящего варианта;
+// варианты другой письменности (ru-Latn для ru) не подходят
+func (ls *LyricsService) Localize(ctx context.Context, song *models.Song, prefs []language.Tag) (*models.Song, error) {
+	if len(prefs) == 0 {
+		return song, nil
+	}
+
+	variants, err := ls.repo.GetVariants(ctx, song.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(variants) == 0 {
+		return song, nil
+	}
+
+	// первый тег - оригинал, к нему же matcher приводит запросы без совпадений
+	original, err := language.Parse(song.Lang)
+	if err != nil {
+		original = language.Und
+	}
+
+	supported := []language.Tag{original}
+	for _, variant := range variants {
+		supported = append(supported, language.Make(variant.Lang))
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(prefs...)
+	if index == 0 || confidence < language.High {
+		return song, nil
+	}
+
+	localized := *song
+	localized.Text = variants[index-1].Text
+	localized.LyricsLang = variants[index-1].Lang
+
+	return &localized, nil
+}
+
+func (ls *LyricsService) getLyrics(ctx context.Context, songId int64) (*SongLyrics, *models.Song, error) {
+	lines, err := ls.repo.GetLines(ctx, songId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	song, err := ls.songs.GetById(ctx, songId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &SongLyrics{
+		SongId: songId,
+		Synced: len(lines) > 0,
+		Text:   song.Text,
+		Lines:  lines,
+	}, song, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type PlaylistsWithPagination struct {
+	Result      []*models.Playlist `json:"result"`
+	CurrentPage int                `json:"current_page"`
+	PagesAmount int                `json:"pages_amount"`
+}
+
+type PlaylistWithPagination struct {
+	*models.Playlist
+	Result      []*models.PlaylistEntry `json:"result"`
+	CurrentPage int                     `json:"current_page"`
+	PagesAmount int                     `json:"pages_amount"`
+}
+
+type PlaylistService struct {
+	repo models.PlaylistRepository
+}
+
+func NewPlaylistService(pr models.PlaylistRepository) *PlaylistService {
+	return &PlaylistService{
+		repo: pr,
+	}
+}
+
+func (ps *PlaylistService) AddPlaylist(ctx context.Context, playlist *models.Playlist) error {
+	err := ps.repo.Add(ctx, playlist)
+	if err != nil {
+		return fmt.Errorf("database error while creating a playlist: %w", err)
+	}
+
+	return nil
+}
+
+func (ps *PlaylistService) GetAllPlaylistsWithPagination(ctx context.Context, limit int, page int) (*PlaylistsWithPagination, error) {
+	offset := (page * limit) - limit
+	instances, count, err := ps.repo.GetWithPagination(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlaylistsWithPagination{
+		Result:      instances,
+		PagesAmount: pagesAmount(count, limit),
+		CurrentPage: page,
+	}, nil
+}
+
+func (ps *PlaylistService) GetPlaylistWithPagination(ctx context.Context, id int64, limit int, page int) (*PlaylistWithPagination, error) {
+	playlist, err := ps.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page * limit) - limit
+	entries, count, err := ps.repo.GetEntriesWithPagination(ctx, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlaylistWithPagination{
+		Playlist:    playlist,
+		Result:      entries,
+		PagesAmount: pagesAmount(count, limit),
+		CurrentPage: page,
+	}, nil
+}
+
+func (ps *PlaylistService) RenamePlaylist(ctx context.Context, id int64, name string) (*models.Playlist, error) {
+	return ps.repo.Rename(ctx, id, name)
+}
+
+func (ps *PlaylistService) DeletePlaylist(ctx context.Context, id int64) error {
+	return ps.repo.Delete(ctx, id)
+}
+
+func (ps *PlaylistService) AddPlaylistEntry(ctx context.Context, playlistId int64, songId int64, position int) (*models.PlaylistEntry, error) {
+	return ps.repo.InsertEntry(ctx, playlistId, songId, position)
+}
+
+func (ps *PlaylistService) MovePlaylistEntry(ctx context.Context, playlistId int64, entryId int64, position int) error {
+	return ps.repo.MoveEntry(ctx, playlistId, entryId, position)
+}
+
+func (ps *PlaylistService) RemovePlaylistEntry(ctx context.Context, playlistId int64, entryId int64) error {
+	return ps.repo.RemoveEntry(ctx, playlistId, entryId)
+}
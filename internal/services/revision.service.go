@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/textdiff"
+)
+
+type SongRevisionsWithPagination struct {
+	Result      []*models.SongRevision `json:"result"`
+	CurrentPage int                    `json:"current_page"`
+	PagesAmount int                    `json:"pages_amount"`
+}
+
+// SongFieldChange - значение поля снимка в предыдущей и в выбранной ревизии, null - значения не было
+type SongFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type SongRevisionDiff struct {
+	Revision *models.SongRevision `json:"revision"`
+	// номер предыдущей ревизии, 0 у первой ревизии песни
+	Previous int               `json:"previous"`
+	Changes  []SongFieldChange `json:"changes"`
+	// построчная разница текста песни, только если текст изменился
+	TextDiff []textdiff.Line `json:"text_diff,omitempty"`
+}
+
+type RevisionService struct {
+	repo models.SongRevisionRepository
+}
+
+func NewRevisionService(rr models.SongRevisionRepository) *RevisionService {
+	return &RevisionService{
+		repo: rr,
+	}
+}
+
+func (rs *RevisionService) GetRevisions(ctx context.Context, songId int64, limit int, page int) (*SongRevisionsWithPagination, error) {
+	offset := (page - 1) * limit
+	revisions, count, err := rs.repo.GetBySongId(ctx, songId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SongRevisionsWithPagination{
+		Result:      revisions,
+		CurrentPage: page,
+		PagesAmount: pagesAmount(count, limit),
+	}, nil
+}
+
+// GetRevisionDiff возвращает ревизию и ее отличия от предыдущей ревизии песни
+func (rs *RevisionService) GetRevisionDiff(ctx context.Context, songId int64, revision int) (*SongRevisionDiff, error) {
+	current, err := rs.repo.GetByRevision(ctx, songId, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SongRevisionDiff{
+		Revision: current,
+		Changes:  make([]SongFieldChange, 0),
+	}
+
+	previous := &models.SongSnapshot{}
+	if revision > 1 {
+		prev, err := rs.repo.GetByRevision(ctx, songId, revision-1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get previous revision: %w", err)
+		}
+
+		diff.Previous = prev.Revision
+		previous = prev.Snapshot
+	}
+
+	from, to := snapshotFields(previous), snapshotFields(current.Snapshot)
+	for _, field := range snapshotFieldNames {
+		if from[field] == to[field] {
+			continue
+		}
+
+		diff.Changes = append(diff.Changes, SongFieldChange{Field: field, From: from[field], To: to[field]})
+		if field == "text" {
+			diff.TextDiff = textdiff.Lines(previous.Text, current.Snapshot.Text)
+		}
+	}
+
+	return diff, nil
+}
+
+// RestoreRevision возвращает песне редактируемые поля из снимка ревизии. Куплеты собираются из текста заново,
+// восстановление записывается новой ревизией. Снимок проверяется правилами записи песни: ревизия, записанная
+// до появления правила, может его нарушать
+func (rs *RevisionService) RestoreRevision(ctx context.Context, songId int64, revision int, ifMatch []int) (*models.Song, error) {
+	found, err := rs.repo.GetByRevision(ctx, songId, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	song := models.Song{
+		Song:  found.Snapshot.Song,
+		Group: found.Snapshot.Group,
+		Text:  found.Snapshot.Text,
+		Link:  found.Snapshot.Link,
+	}
+
+	if found.Snapshot.ReleaseDate != nil {
+		releaseDate, err := time.Parse(time.DateOnly, *found.Snapshot.ReleaseDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid release date in revision snapshot: %w", err)
+		}
+		song.ReleaseDate = &releaseDate
+	}
+
+	if found.Snapshot.Lang != nil {
+		song.Lang = *found.Snapshot.Lang
+	}
+
+	err = validateSong(&song)
+	if err != nil {
+		return nil, err
+	}
+
+	splitLyrics(&song)
+
+	return rs.repo.Restore(ctx, songId, revision, &song, ifMatch)
+}
+
+var snapshotFieldNames = []string{"song", "group", "text", "link", "release_date", "lang"}
+
+// snapshotFields раскладывает снимок по полям; пустые необязательные поля - nil
+func snapshotFields(snapshot *models.SongSnapshot) map[string]any {
+	fields := map[string]any{
+		"song":         snapshot.Song,
+		"group":        snapshot.Group,
+		"text":         snapshot.Text,
+		"link":         snapshot.Link,
+		"release_date": nil,
+		"lang":         nil,
+	}
+
+	if snapshot.ReleaseDate != nil {
+		fields["release_date"] = *snapshot.ReleaseDate
+	}
+
+	if snapshot.Lang != nil {
+		fields["lang"] = *snapshot.Lang
+	}
+
+	return fields
+}
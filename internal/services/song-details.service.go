@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"time"
@@ -13,55 +16,104 @@ type SongDetailsApiService interface {
 	FindSongDetails(ctx context.Context, group string, song string) (*SongDetails, error)
 }
 
+var (
+	ErrSongDetailsBadRequest  = errors.New("song details api rejected the request")
+	ErrSongDetailsNotFound    = errors.New("song details are not found")
+	ErrSongDetailsUnavailable = errors.New("song details api is unavailable")
+	ErrSongDetailsCircuitOpen = errors.New("song details api is temporarily disabled after repeated failures")
+)
+
+// формат даты, который отдает API деталей песни (например, 16.07.2006)
+const SongDetailsReleaseDateLayout = "02.01.2006"
+
 type SongDetails struct {
 	ReleaseDate time.Time `json:"releaseDate"`
 	Text        string    `json:"text"`
 	Link        string    `json:"link"`
 }
 
-type SongDetailsMockApiService struct{}
+func (sd *SongDetails) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ReleaseDate string `json:"releaseDate"`
+		Text        string `json:"text"`
+		Link        string `json:"link"`
+	}
 
-func NewSongDetailsMockApiService() *SongDetailsMockApiService {
-	return &SongDetailsMockApiService{}
-}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
 
-const (
-	apiUrl = "https://some-fancy-url.com"
-)
+	sd.Text = raw.Text
+	sd.Link = raw.Link
+	sd.ReleaseDate = time.Time{}
+
+	if raw.ReleaseDate == "" {
+		return nil
+	}
+
+	for _, layout := range []string{SongDetailsReleaseDateLayout, time.DateOnly, time.RFC3339} {
+		date, err := time.Parse(layout, raw.ReleaseDate)
+		if err == nil {
+			sd.ReleaseDate = date
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid release date format: %s", raw.ReleaseDate)
+}
 
 const (
 	songInfoRoute = "/info"
 )
 
-func (*SongDetailsMockApiService) FindSongDetails(ctx context.Context, group string, song string) (*SongDetails, error) {
-	url, err := url.Parse(apiUrl)
+type SongDetailsHttpApiService struct {
+	client  requests.Doer
+	baseUrl string
+}
+
+func NewSongDetailsHttpApiService(client requests.Doer, baseUrl string) *SongDetailsHttpApiService {
+	return &SongDetailsHttpApiService{
+		client:  client,
+		baseUrl: baseUrl,
+	}
+}
+
+func (s *SongDetailsHttpApiService) FindSongDetails(ctx context.Context, group string, song string) (*SongDetails, error) {
+	infoUrl, err := url.JoinPath(s.baseUrl, songInfoRoute)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid song details api url: %w", err)
 	}
 
-	values := url.Query()
-	values.Set("group", group)
-	values.Set("song", song)
-
-	url.RawQuery = values.Encode()
-
-	// FYI: игнорирую ошибку и результат, из-за мока API-шки
-	_, _ = requests.RequestWithJSON[any, SongDetails](
-		ctx,
-		http.DefaultClient,
-		apiUrl+songInfoRoute,
-		nil,
-		nil,
-	)
-
-	// date mock
-	date := time.Date(2006, 7, 16, 0, 0, 0, 0, &time.Location{})
-	// song details mock
-	mock := &SongDetails{
-		ReleaseDate: date,
-		Text:        "Ooh baby, don't you know I suffer?\\nOoh baby, can you hear me moan?\\nYou caught me under false pretenses\\nHow long before you let me go?\\n\\nOoh\\nYou set my soul alight\\nOoh\\nYou set my soul alight",
-		Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	res, err := requests.Get[SongDetails](ctx, s.client, infoUrl, &requests.Options{
+		Query: url.Values{
+			"group": {group},
+			"song":  {song},
+		},
+		AcceptedStatuses: []int{http.StatusOK},
+	})
+
+	var httpErr *requests.HTTPError
+	switch {
+	case err == nil:
+		return &res.Body, nil
+	case errors.Is(err, requests.ErrCircuitOpen):
+		return nil, fmt.Errorf("%w: %w", ErrSongDetailsCircuitOpen, err)
+	case errors.As(err, &httpErr):
+		return nil, mapSongDetailsHttpError(httpErr)
+	default:
+		return nil, fmt.Errorf("%w: %w", ErrSongDetailsUnavailable, err)
 	}
+}
 
-	return mock, nil
+func mapSongDetailsHttpError(err *requests.HTTPError) error {
+	switch {
+	case err.StatusCode == http.StatusBadRequest:
+		return fmt.Errorf("%w: %s", ErrSongDetailsBadRequest, err.Body)
+	case err.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrSongDetailsNotFound, err.Body)
+	case err.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: %w", ErrSongDetailsUnavailable, err)
+	default:
+		return fmt.Errorf("unexpected response from song details api: %w", err)
+	}
 }
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shlmvgleb/em-task/pkg/requests"
+)
+
+func newTestSongDetailsService(t *testing.T, handler http.HandlerFunc, config requests.ClientConfig) *SongDetailsHttpApiService {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewSongDetailsHttpApiService(requests.NewClient(server.Client(), config), server.URL)
+}
+
+func TestFindSongDetailsStatuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "bad request", status: http.StatusBadRequest, wantErr: ErrSongDetailsBadRequest},
+		{name: "not found", status: http.StatusNotFound, wantErr: ErrSongDetailsNotFound},
+		{name: "internal error", status: http.StatusInternalServerError, wantErr: ErrSongDetailsUnavailable},
+		{name: "service unavailable", status: http.StatusServiceUnavailable, wantErr: ErrSongDetailsUnavailable},
+		{name: "unexpected status", status: http.StatusTeapot},
+		{name: "no content is not accepted", status: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestSongDetailsService(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}, requests.ClientConfig{})
+
+			details, err := service.FindSongDetails(context.Background(), "Muse", "Supermassive Black Hole")
+			if err == nil {
+				t.Fatalf("FindSongDetails() = %+v, want error", details)
+			}
+
+			known := []error{ErrSongDetailsBadRequest, ErrSongDetailsNotFound, ErrSongDetailsUnavailable, ErrSongDetailsCircuitOpen}
+			for _, target := range known {
+				if errors.Is(err, target) != (target == tt.wantErr) {
+					t.Errorf("FindSongDetails() error = %v, want %v", err, tt.wantErr)
+				}
+			}
+
+			var httpErr *requests.HTTPError
+			if !errors.As(err, &httpErr) && tt.wantErr != ErrSongDetailsBadRequest && tt.wantErr != ErrSongDetailsNotFound {
+				t.Errorf("FindSongDetails() error = %v, want wrapped HTTPError", err)
+			}
+		})
+	}
+}
+
+func TestFindSongDetailsRequest(t *testing.T) {
+	service := newTestSongDetailsService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != songInfoRoute {
+			t.Errorf("path = %s, want %s", r.URL.Path, songInfoRoute)
+		}
+
+		if group, song := r.URL.Query().Get("group"), r.URL.Query().Get("song"); group != "Muse & Co" || song != "Uprising?" {
+			t.Errorf("query group = %q, song = %q", group, song)
+		}
+
+		_, _ = w.Write([]byte(`{"releaseDate":"16.07.2006","text":"text","link":"https://example.com"}`))
+	}, requests.ClientConfig{})
+
+	details, err := service.FindSongDetails(context.Background(), "Muse & Co", "Uprising?")
+	if err != nil {
+		t.Fatalf("FindSongDetails() error = %v", err)
+	}
+
+	want := SongDetails{
+		ReleaseDate: time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC),
+		Text:        "text",
+		Link:        "https://example.com",
+	}
+	if *details != want {
+		t.Errorf("FindSongDetails() = %+v, want %+v", *details, want)
+	}
+}
+
+func TestSongDetailsReleaseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "api format", body: `{"releaseDate":"16.07.2006"}`, want: time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "iso date", body: `{"releaseDate":"2006-07-16"}`, want: time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)},
+		{
+			name: "rfc3339",
+			body: `{"releaseDate":"2006-07-16T10:30:00Z"}`,
+			want: time.Date(2006, 7, 16, 10, 30, 0, 0, time.UTC),
+		},
+		{name: "empty", body: `{"releaseDate":""}`},
+		{name: "missing", body: `{}`},
+		{name: "invalid", body: `{"releaseDate":"16/07/2006"}`, wantErr: true},
+		{name: "impossible date", body: `{"releaseDate":"31.02.2006"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestSongDetailsService(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.body))
+			}, requests.ClientConfig{})
+
+			details, err := service.FindSongDetails(context.Background(), "Muse", "Uprising")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindSongDetails() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if err != nil {
+				if !errors.Is(err, ErrSongDetailsUnavailable) {
+					t.Errorf("FindSongDetails() error = %v, want ErrSongDetailsUnavailable", err)
+				}
+				return
+			}
+
+			if !details.ReleaseDate.Equal(tt.want) {
+				t.Errorf("ReleaseDate = %s, want %s", details.ReleaseDate, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindSongDetailsTimeout(t *testing.T) {
+	service := newTestSongDetailsService(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}, requests.ClientConfig{Timeout: 50 * time.Millisecond})
+
+	_, err := service.FindSongDetails(context.Background(), "Muse", "Uprising")
+	if !errors.Is(err, ErrSongDetailsUnavailable) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("FindSongDetails() error = %v, want ErrSongDetailsUnavailable caused by timeout", err)
+	}
+}
+
+func TestFindSongDetailsCircuitOpen(t *testing.T) {
+	service := newTestSongDetailsService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}, requests.ClientConfig{BreakerFailureThreshold: 1, BreakerOpenTimeout: time.Minute})
+
+	_, err := service.FindSongDetails(context.Background(), "Muse", "Uprising")
+	if !errors.Is(err, ErrSongDetailsUnavailable) {
+		t.Fatalf("first FindSongDetails() error = %v, want ErrSongDetailsUnavailable", err)
+	}
+
+	_, err = service.FindSongDetails(context.Background(), "Muse", "Uprising")
+	if !errors.Is(err, ErrSongDetailsCircuitOpen) {
+		t.Fatalf("second FindSongDetails() error = %v, want ErrSongDetailsCircuitOpen", err)
+	}
+}
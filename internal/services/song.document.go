@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/validation"
+	"golang.org/x/text/language"
+)
+
+var ErrInvalidSongDocument = errors.New("invalid song document")
+
+const (
+	maxSongTitleLength = 255
+	maxSongTextLength  = 100_000
+	maxSongLinkLength  = 2048
+)
+
+type SongPatchFormat string
+
+const (
+	// application/merge-patch+json, RFC 7396
+	SongMergePatch SongPatchFormat = "merge-patch"
+	// application/json-patch+json, RFC 6902
+	SongJsonPatch SongPatchFormat = "json-patch"
+)
+
+// SongDocument - редактируемые поля песни: цель JSON Merge Patch и JSON Patch и тело PUT.
+// Строки без значения пустые, release_date и lang без значения - null
+// @Description Редактируемые поля песни. null или отсутствие поля очищает его, song и group обязательны.
+// @Description Ошибки валидации возвращаются в details с кодами required, too_long, invalid_url, invalid_date, future_date, invalid_language.
+type SongDocument struct {
+	Song  string `json:"song" maxLength:"255"`
+	Group string `json:"group" maxLength:"255"`
+	Text  string `json:"text" maxLength:"100000"`
+	// абсолютная http(s) ссылка
+	Link string `json:"link" maxLength:"2048"`
+	// собственная дата выпуска песни YYYY-MM-DD не позже сегодняшней, null - дата берется из альбома
+	ReleaseDate *string `json:"release_date" example:"2006-07-16"`
+	// язык оригинального текста, тег BCP 47
+	Lang *string `json:"lang" example:"ru"`
+}
+
+// newSongDocument собирает документ из песни, переданной в правку: ее дата выпуска - собственная дата песни
+func newSongDocument(song *models.Song) *SongDocument {
+	doc := &SongDocument{
+		Song:  song.Song,
+		Group: song.Group,
+		Text:  song.Text,
+		Link:  song.Link,
+	}
+
+	if song.ReleaseDate != nil {
+		date := song.ReleaseDate.Format(time.DateOnly)
+		doc.ReleaseDate = &date
+	}
+
+	if song.Lang != "" {
+		doc.Lang = &song.Lang
+	}
+
+	return doc
+}
+
+// decodeSongDocument разбирает документ, неизвестные поля и значения не того типа - ошибка
+func decodeSongDocument(data []byte) (*SongDocument, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	doc := &SongDocument{}
+	err := decoder.Decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSongDocument, err)
+	}
+
+	return doc, nil
+}
+
+// validate проверяет поля документа правилами песни, общими для всех запросов записи
+func (doc *SongDocument) validate() error {
+	var releaseDate, lang string
+	if doc.ReleaseDate != nil {
+		releaseDate = *doc.ReleaseDate
+	}
+
+	if doc.Lang != nil {
+		lang = strings.TrimSpace(*doc.Lang)
+	}
+
+	return validation.Validate(
+		validation.Field("song", doc.Song, validation.Required, validation.MaxLength(maxSongTitleLength)),
+		validation.Field("group", doc.Group, validation.Required, validation.MaxLength(maxSongTitleLength)),
+		validation.Field("text", doc.Text, validation.MaxLength(maxSongTextLength)),
+		validation.Field("link", doc.Link, validation.MaxLength(maxSongLinkLength), validation.URL),
+		validation.Field("release_date", releaseDate, validation.Date, validation.NotFutureDate),
+		validation.Field("lang", lang, validation.Language),
+	)
+}
+
+// apply проверяет документ и переносит его в песню. Куплеты разбираются заново, только если текст изменился,
+// иначе у песни сохраняются виды куплетов и синхронизация
+func (doc *SongDocument) apply(song *models.Song) error {
+	err := doc.validate()
+	if err != nil {
+		return err
+	}
+
+	var releaseDate *time.Time
+	if doc.ReleaseDate != nil && *doc.ReleaseDate != "" {
+		date, _ := time.Parse(time.DateOnly, *doc.ReleaseDate)
+		releaseDate = &date
+	}
+
+	lang := ""
+	if doc.Lang != nil && strings.TrimSpace(*doc.Lang) != "" {
+		lang = language.Make(strings.TrimSpace(*doc.Lang)).String()
+	}
+
+	song.Song = doc.Song
+	song.Group = doc.Group
+	song.Link = doc.Link
+	song.ReleaseDate = releaseDate
+	song.Lang = lang
+
+	if doc.Text != song.Text {
+		song.Text = doc.Text
+		splitLyrics(song)
+	}
+
+	return nil
+}
+
+// validateSong проверяет песню теми же правилами, что и документ SongDocument
+func validateSong(song *models.Song) error {
+	return newSongDocument(song).validate()
+}
@@ -2,38 +2,91 @@ package services
 
 import (
 	"context"
-	"errors"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math"
 	"strings"
 
 	"github.com/shlmvgleb/em-task/internal/models"
+	"github.com/shlmvgleb/em-task/pkg/cursor"
+	"github.com/shlmvgleb/em-task/pkg/jsonpatch"
+	"github.com/shlmvgleb/em-task/pkg/lyrics"
 )
 
 type SongsWithPagination struct {
 	Result      []*models.Song `json:"result"`
 	CurrentPage int            `json:"current_page"`
-	PagesAmount int            `json:"pages_amount"`
+	// 0, если количество не считалось (count=none)
+	PagesAmount int `json:"pages_amount"`
+	// количество найденных песен, при count=estimate - приблизительное
+	TotalItems *int `json:"total_items,omitempty"`
+	// количество найденных песен по жанрам и тегам, заполняется по запросу
+	Facets *models.SongFacets `json:"facets,omitempty"`
+	// курсоры соседних страниц, заполняются только при пагинации курсором
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// songCursor - содержимое курсора списка песен: сортировка и фильтр, для которых он выдан, и ключи граничной песни
+type songCursor struct {
+	Sort     string   `json:"s"`
+	Filter   string   `json:"f"`
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+type LyricsMatchesWithPagination struct {
+	Result      []*models.LyricsMatch `json:"result"`
+	CurrentPage int                   `json:"current_page"`
+	PagesAmount int                   `json:"pages_amount"`
 }
 
 type SongByIdWithVersePagination struct {
-	Song         *models.Song `json:"song"`
-	CurrentVerse int          `json:"current_verse"`
-	VersesAmount int          `json:"verses_amount"`
+	// песня без текста, текст выбранных куплетов - в Verses
+	Song *models.Song `json:"song"`
+	// выбранные куплеты по порядку, позиция куплета - его номер в песне
+	Verses []*models.Verse `json:"verses"`
+	// страница и количество страниц по PerPage куплетов, при выборе диапазоном не заполняются
+	CurrentPage  int `json:"current_page,omitempty"`
+	PerPage      int `json:"per_page,omitempty"`
+	PagesAmount  int `json:"pages_amount,omitempty"`
+	VersesAmount int `json:"verses_amount"`
+}
+
+// VerseSelection задает выбранные куплеты: страницу Page по PerPage куплетов
+// или, если From больше 0, диапазон позиций From-To включительно
+type VerseSelection struct {
+	Page    int
+	PerPage int
+	From    int
+	To      int
 }
 
 type SongService struct {
-	repo models.SongRepository
+	repo    models.SongRepository
+	verses  models.VerseRepository
+	cursors *cursor.Signer
 }
 
-func NewSongService(sr models.SongRepository) *SongService {
+func NewSongService(sr models.SongRepository, vr models.VerseRepository, cursors *cursor.Signer) *SongService {
 	return &SongService{
-		repo: sr,
+		repo:    sr,
+		verses:  vr,
+		cursors: cursors,
 	}
 }
 
 func (ss *SongService) AddSong(ctx context.Context, song *models.Song) error {
-	err := ss.repo.Add(ctx, song)
+	err := validateSong(song)
+	if err != nil {
+		return err
+	}
+
+	splitLyrics(song)
+
+	err = ss.repo.Add(ctx, song)
 	if err != nil {
 		return fmt.Errorf("database error while creating a song: %w", err)
 	}
@@ -41,22 +94,143 @@ func (ss *SongService) AddSong(ctx context.Context, song *models.Song) error {
 	return nil
 }
 
-func (ss *SongService) GetAllSongsWithPagination(ctx context.Context, searchQuery string, limit int, page int) (*SongsWithPagination, error) {
+func (ss *SongService) GetAllSongsWithPagination(
+	ctx context.Context,
+	filter models.SongFilter,
+	sort []models.SongSortKey,
+	count models.SongCount,
+	withFacets bool,
+	limit int, page int,
+) (*SongsWithPagination, error) {
 	offset := (page * limit) - limit
-	instances, count, err := ss.repo.GetWithSearchAndPagination(ctx, searchQuery, limit, offset)
+	instances, total, err := ss.repo.GetWithFilterAndPagination(ctx, filter, sort, count, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
-	pagesCount := math.Floor(float64(count) / float64(limit))
-	if count%limit != 0 {
-		pagesCount += 1
+	paginated := &SongsWithPagination{
+		Result:      instances,
+		CurrentPage: page,
+		TotalItems:  total,
 	}
 
-	return &SongsWithPagination{
-		Result:      instances,
-		PagesAmount: int(pagesCount),
+	if total != nil {
+		paginated.PagesAmount = pagesAmount(*total, limit)
+	}
+
+	if withFacets {
+		paginated.Facets, err = ss.repo.GetFacets(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return paginated, nil
+}
+
+// GetAllSongsWithCursor возвращает страницу песен после курсора token (пустой - первая страница).
+// В отличие от пагинации по страницам, вставка и удаление песен между запросами не приводят к пропускам и повторам.
+func (ss *SongService) GetAllSongsWithCursor(
+	ctx context.Context,
+	filter models.SongFilter,
+	sort []models.SongSortKey,
+	token string,
+	count models.SongCount,
+	withFacets bool,
+	limit int,
+) (*SongsWithPagination, error) {
+	signature := sortSignature(models.EffectiveSongSort(sort, filter))
+	filterHash, err := songFilterHash(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyset *models.SongKeyset
+	if token != "" {
+		decoded := songCursor{}
+		err := ss.cursors.Decode(token, &decoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", models.ErrInvalidSongCursor, err)
+		}
+
+		// ключи курсора имеют смысл только для той сортировки и того фильтра, с которыми он был выдан
+		if decoded.Sort != signature {
+			return nil, fmt.Errorf("%w: cursor was issued for sort %q", models.ErrInvalidSongCursor, decoded.Sort)
+		}
+
+		if decoded.Filter != filterHash {
+			return nil, fmt.Errorf("%w: cursor was issued for another filter", models.ErrInvalidSongCursor)
+		}
+
+		keyset = &models.SongKeyset{
+			Values:   decoded.Values,
+			Backward: decoded.Backward,
+		}
+	}
+
+	page, err := ss.repo.GetWithFilterAndKeyset(ctx, filter, sort, keyset, count, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext, hasPrev := page.HasMore, keyset != nil
+	if keyset != nil && keyset.Backward {
+		hasNext, hasPrev = true, page.HasMore
+	}
+
+	paginated := &SongsWithPagination{
+		Result:     page.Songs,
+		TotalItems: page.Total,
+	}
+
+	if hasNext && len(page.Songs) > 0 {
+		paginated.NextCursor, err = ss.cursors.Encode(songCursor{Sort: signature, Filter: filterHash, Values: page.Last})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if hasPrev && len(page.Songs) > 0 {
+		paginated.PrevCursor, err = ss.cursors.Encode(songCursor{Sort: signature, Filter: filterHash, Values: page.First, Backward: true})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if withFacets {
+		paginated.Facets, err = ss.repo.GetFacets(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return paginated, nil
+}
+
+func (ss *SongService) GetSongSuggestions(ctx context.Context, input string, limit int) ([]*models.SongSuggestion, error) {
+	suggestions, err := ss.repo.GetSuggestions(ctx, input, limit)
+	if err != nil {
+		return nil, fmt.Errorf("database error while fetching song suggestions: %w", err)
+	}
+
+	return suggestions, nil
+}
+
+func (ss *SongService) SearchLyrics(
+	ctx context.Context,
+	query models.LyricsQuery,
+	limit int, page int,
+) (*LyricsMatchesWithPagination, error) {
+	offset := (page * limit) - limit
+	matches, count, err := ss.repo.SearchLyrics(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("database error while searching lyrics: %w", err)
+	}
+
+	return &LyricsMatchesWithPagination{
+		Result:      matches,
 		CurrentPage: page,
+		PagesAmount: pagesAmount(count, limit),
 	}, nil
 }
 
@@ -69,36 +243,195 @@ func (ss *SongService) GetSongById(ctx context.Context, id int64) (*models.Song,
 	return song, nil
 }
 
-func (ss *SongService) CreateVersePagination(song *models.Song, page int) (*SongByIdWithVersePagination, error) {
-	verses := strings.Split(song.Text, "\\n\\n")
-
-	if len(verses)-1 < page-1 || page < 1 {
-		return nil, errors.New("invalid verse page")
+// CreateVersePagination выбирает куплеты песни. Переданная песня не изменяется, в ответ попадает ее копия без текста
+func (ss *SongService) CreateVersePagination(ctx context.Context, song *models.Song, selection VerseSelection) (*SongByIdWithVersePagination, error) {
+	verses, err := ss.songVerses(ctx, song)
+	if err != nil {
+		return nil, err
 	}
 
-	song.Text = verses[page-1]
+	view := *song
+	view.Text = ""
+	view.Verses = nil
 
-	return &SongByIdWithVersePagination{
-		Song:         song,
-		CurrentVerse: page,
+	paginated := &SongByIdWithVersePagination{
+		Song:         &view,
 		VersesAmount: len(verses),
-	}, nil
+	}
+
+	from, to := selection.From, selection.To
+	if from == 0 {
+		paginated.CurrentPage = selection.Page
+		paginated.PerPage = selection.PerPage
+		// у песни без текста есть единственная пустая страница
+		paginated.PagesAmount = max(pagesAmount(len(verses), selection.PerPage), 1)
+
+		from = (selection.Page-1)*selection.PerPage + 1
+		to = selection.Page * selection.PerPage
+	}
+
+	if len(verses) == 0 && from == 1 {
+		paginated.Verses = make([]*models.Verse, 0)
+		return paginated, nil
+	}
+
+	if from < 1 || from > len(verses) || to < from {
+		return nil, fmt.Errorf("verses %d-%d of %d: %w", from, to, len(verses), models.ErrVerseNotFound)
+	}
+
+	paginated.Verses = verses[from-1 : min(to, len(verses))]
+
+	return paginated, nil
+}
+
+// UpdateSong переносит в песню непустые поля song, пустое значение означает, что поле не передано,
+// поэтому очистить поле нельзя. Вместо него используются PatchSong и ReplaceSong
+func (ss *SongService) UpdateSong(ctx context.Context, id int64, song models.Song, ifMatch []int) (*models.Song, error) {
+	// пустой текст означает, что текст не передан и не меняется
+	if song.Text != "" {
+		splitLyrics(&song)
+	}
+
+	return ss.repo.Update(ctx, id, func(saved *models.Song) error {
+		if song.Song != "" {
+			saved.Song = song.Song
+		}
+
+		if song.Group != "" {
+			saved.Group = song.Group
+		}
+
+		if song.Text != "" {
+			saved.Text = song.Text
+			saved.Verses = song.Verses
+		}
+
+		if song.Link != "" {
+			saved.Link = song.Link
+		}
+
+		if song.ReleaseDate != nil {
+			saved.ReleaseDate = song.ReleaseDate
+		}
+
+		if song.Lang != "" {
+			saved.Lang = song.Lang
+		}
+
+		return validateSong(saved)
+	}, ifMatch)
+}
+
+// PatchSong применяет к редактируемым полям песни (SongDocument) JSON Merge Patch или JSON Patch
+func (ss *SongService) PatchSong(ctx context.Context, id int64, format SongPatchFormat, patch []byte, ifMatch []int) (*models.Song, error) {
+	return ss.repo.Update(ctx, id, func(saved *models.Song) error {
+		doc, err := json.Marshal(newSongDocument(saved))
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case SongMergePatch:
+			doc, err = jsonpatch.MergePatch(doc, patch)
+		case SongJsonPatch:
+			doc, err = jsonpatch.Apply(doc, patch)
+		default:
+			return fmt.Errorf("unknown song patch format %q", format)
+		}
+		if err != nil {
+			return err
+		}
+
+		patched, err := decodeSongDocument(doc)
+		if err != nil {
+			return err
+		}
+
+		return patched.apply(saved)
+	}, ifMatch)
 }
 
-func (ss *SongService) UpdateSong(ctx context.Context, id int64, song models.Song) (*models.Song, error) {
-	updated, err := ss.repo.Update(ctx, id, &song)
+// ReplaceSong заменяет редактируемые поля песни документом, непереданные поля очищаются
+func (ss *SongService) ReplaceSong(ctx context.Context, id int64, document []byte, ifMatch []int) (*models.Song, error) {
+	doc, err := decodeSongDocument(document)
 	if err != nil {
 		return nil, err
 	}
 
-	return updated, nil
+	return ss.repo.Update(ctx, id, doc.apply, ifMatch)
 }
 
-func (ss *SongService) DeleteSong(ctx context.Context, id int64) error {
-	err := ss.repo.Delete(ctx, id)
+func (ss *SongService) DeleteSong(ctx context.Context, id int64, ifMatch []int) error {
+	err := ss.repo.Delete(ctx, id, ifMatch)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// songVerses возвращает куплеты песни. У песни с выбранным вариантом текста куплеты - блоки этого текста,
+// они не хранятся отдельно и не имеют id
+func (ss *SongService) songVerses(ctx context.Context, song *models.Song) ([]*models.Verse, error) {
+	if song.LyricsLang == "" {
+		return ss.verses.GetBySongId(ctx, song.Id)
+	}
+
+	localized := models.Song{Text: song.Text}
+	splitLyrics(&localized)
+
+	return localized.Verses, nil
+}
+
+// splitLyrics нормализует текст песни и разбирает его на куплеты, которые сохраняются вместе с песней
+func splitLyrics(song *models.Song) {
+	parsed := lyrics.Parse(song.Text)
+
+	song.Verses = make([]*models.Verse, 0, len(parsed))
+	texts := make([]string, 0, len(parsed))
+	for i, verse := range parsed {
+		song.Verses = append(song.Verses, &models.Verse{
+			Position: i + 1,
+			Kind:     models.VerseKind(verse.Kind),
+			Text:     verse.Text,
+		})
+		texts = append(texts, verse.Text)
+	}
+
+	song.Text = strings.Join(texts, "\n\n")
+}
+
+// sortSignature записывает сортировку в том же виде, в котором она передается в query: "release_date,-created_at"
+func sortSignature(sort []models.SongSortKey) string {
+	parts := make([]string, 0, len(sort))
+	for _, key := range sort {
+		if key.Desc {
+			parts = append(parts, "-"+string(key.Field))
+			continue
+		}
+
+		parts = append(parts, string(key.Field))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// songFilterHash возвращает короткий хеш фильтра, чтобы курсор нельзя было применить к другой выборке
+func songFilterHash(filter models.SongFilter) (string, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal song filter: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
+
+func pagesAmount(count int, limit int) int {
+	pagesCount := math.Floor(float64(count) / float64(limit))
+	if count%limit != 0 {
+		pagesCount += 1
+	}
+
+	return int(pagesCount)
+}
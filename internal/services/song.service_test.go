@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+func TestSongCursorSignature(t *testing.T) {
+	tests := []struct {
+		name     string
+		sort     []models.SongSortKey
+		filter   models.SongFilter
+		wantSort string
+	}{
+		{name: "default order", wantSort: "created_at"},
+		{name: "default fuzzy order", filter: models.SongFilter{Fuzzy: "muse"}, wantSort: "-similarity"},
+		{
+			name:     "explicit order",
+			sort:     []models.SongSortKey{{Field: models.SortByReleaseDate}, {Field: models.SortByCreatedAt, Desc: true}},
+			filter:   models.SongFilter{Fuzzy: "muse"},
+			wantSort: "release_date,-created_at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortSignature(models.EffectiveSongSort(tt.sort, tt.filter)); got != tt.wantSort {
+				t.Errorf("sortSignature() = %q, want %q", got, tt.wantSort)
+			}
+		})
+	}
+}
+
+func TestSongFilterHash(t *testing.T) {
+	hash := func(filter models.SongFilter) string {
+		t.Helper()
+
+		h, err := songFilterHash(filter)
+		if err != nil {
+			t.Fatalf("songFilterHash() error = %v", err)
+		}
+
+		return h
+	}
+
+	base := models.SongFilter{Group: "Muse", Genres: []string{"rock"}}
+	if hash(base) != hash(models.SongFilter{Group: "Muse", Genres: []string{"rock"}}) {
+		t.Error("equal filters have different hashes")
+	}
+
+	others := []models.SongFilter{
+		{},
+		{Group: "Muse"},
+		{Group: "Muse", Genres: []string{"rock"}, Fuzzy: "uprising"},
+		{Group: "Muse", Genres: []string{"rock"}, GroupMatch: models.MatchExact},
+	}
+	for _, other := range others {
+		if hash(base) == hash(other) {
+			t.Errorf("filter %+v has the same hash as %+v", other, base)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package songdetailsfake предоставляет in-memory фейк API деталей песни на базе httptest,
+// чтобы сервис можно было запускать и проверять без доступа к реальному API.
+package songdetailsfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/services"
+)
+
+type songKey struct {
+	group string
+	song  string
+}
+
+type songDetailsResponse struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu         sync.RWMutex
+	songs      map[songKey]services.SongDetails
+	failStatus int
+}
+
+// NewServer запускает фейковый API, заранее заполненный одной песней (Muse - Supermassive Black Hole)
+func NewServer() *Server {
+	s := &Server{
+		songs: make(map[songKey]services.SongDetails),
+	}
+
+	s.AddSong("Muse", "Supermassive Black Hole", services.SongDetails{
+		ReleaseDate: time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC),
+		Text:        "Ooh baby, don't you know I suffer?\\nOoh baby, can you hear me moan?\\nYou caught me under false pretenses\\nHow long before you let me go?\\n\\nOoh\\nYou set my soul alight\\nOoh\\nYou set my soul alight",
+		Link:        "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info", s.handleInfo)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+func (s *Server) AddSong(group string, song string, details services.SongDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.songs[newSongKey(group, song)] = details
+}
+
+// FailWith заставляет фейк отвечать переданным статусом на все запросы, 0 отключает режим
+func (s *Server) FailWith(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failStatus = status
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.failStatus != 0 {
+		http.Error(w, http.StatusText(s.failStatus), s.failStatus)
+		return
+	}
+
+	group := r.URL.Query().Get("group")
+	song := r.URL.Query().Get("song")
+	if group == "" || song == "" {
+		http.Error(w, "group and song are required", http.StatusBadRequest)
+		return
+	}
+
+	details, ok := s.songs[newSongKey(group, song)]
+	if !ok {
+		http.Error(w, "song is not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(songDetailsResponse{
+		ReleaseDate: details.ReleaseDate.Format(services.SongDetailsReleaseDateLayout),
+		Text:        details.Text,
+		Link:        details.Link,
+	})
+}
+
+func newSongKey(group string, song string) songKey {
+	return songKey{
+		group: strings.ToLower(strings.TrimSpace(group)),
+		song:  strings.ToLower(strings.TrimSpace(song)),
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type TagsWithPagination struct {
+	Result      []*models.Tag `json:"result"`
+	CurrentPage int           `json:"current_page"`
+	PagesAmount int           `json:"pages_amount"`
+}
+
+type TagService struct {
+	repo models.TagRepository
+}
+
+func NewTagService(tr models.TagRepository) *TagService {
+	return &TagService{
+		repo: tr,
+	}
+}
+
+func (ts *TagService) AddTag(ctx context.Context, tag *models.Tag) error {
+	err := ts.repo.Add(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("database error while creating a tag: %w", err)
+	}
+
+	return nil
+}
+
+func (ts *TagService) GetAllTagsWithPagination(ctx context.Context, limit int, page int) (*TagsWithPagination, error) {
+	offset := (page * limit) - limit
+	instances, count, err := ts.repo.GetWithPagination(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TagsWithPagination{
+		Result:      instances,
+		PagesAmount: pagesAmount(count, limit),
+		CurrentPage: page,
+	}, nil
+}
+
+func (ts *TagService) DeleteTag(ctx context.Context, id int64) error {
+	return ts.repo.Delete(ctx, id)
+}
+
+func (ts *TagService) SetSongTags(ctx context.Context, songId int64, names []string) error {
+	return ts.repo.SetSongTags(ctx, songId, names)
+}
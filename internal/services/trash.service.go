@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+	log "github.com/sirupsen/logrus"
+)
+
+type TrashConfig struct {
+	// сколько песня хранится в корзине до окончательного удаления
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+type TrashedSongsWithPagination struct {
+	Result      []*models.Song `json:"result"`
+	CurrentPage int            `json:"current_page"`
+	PagesAmount int            `json:"pages_amount"`
+}
+
+type TrashService struct {
+	repo   models.SongTrashRepository
+	config TrashConfig
+}
+
+func NewTrashService(tr models.SongTrashRepository, config TrashConfig) *TrashService {
+	return &TrashService{
+		repo:   tr,
+		config: config,
+	}
+}
+
+func (ts *TrashService) GetTrash(ctx context.Context, limit int, page int) (*TrashedSongsWithPagination, error) {
+	offset := (page - 1) * limit
+	songs, count, err := ts.repo.GetTrashWithPagination(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TrashedSongsWithPagination{
+		Result:      songs,
+		CurrentPage: page,
+		PagesAmount: pagesAmount(count, limit),
+	}, nil
+}
+
+func (ts *TrashService) RestoreSong(ctx context.Context, id int64) (*models.Song, error) {
+	return ts.repo.RestoreFromTrash(ctx, id)
+}
+
+// StartPurger запускает очистку корзины раз в PurgeInterval до отмены ctx.
+// Возвращаемая функция дожидается завершения очистки.
+func (ts *TrashService) StartPurger(ctx context.Context) (wait func()) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ts.runPurger(ctx)
+	}()
+
+	log.Infof("Started trash purger, retention %s", ts.config.Retention)
+	return wg.Wait
+}
+
+func (ts *TrashService) runPurger(ctx context.Context) {
+	for {
+		purged, err := ts.repo.PurgeTrash(ctx, time.Now().Add(-ts.config.Retention))
+		if err != nil && ctx.Err() == nil {
+			log.Errorf("trash purger: %s", err)
+		}
+
+		if purged > 0 {
+			log.Infof("Purged %d songs from trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ts.config.PurgeInterval):
+		}
+	}
+}
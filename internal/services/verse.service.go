@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+
+	"github.com/shlmvgleb/em-task/internal/models"
+)
+
+type VerseService struct {
+	repo models.VerseRepository
+}
+
+func NewVerseService(vr models.VerseRepository) *VerseService {
+	return &VerseService{
+		repo: vr,
+	}
+}
+
+func (vs *VerseService) GetSongVerses(ctx context.Context, songId int64) ([]*models.Verse, error) {
+	return vs.repo.GetBySongId(ctx, songId)
+}
+
+func (vs *VerseService) AddVerse(ctx context.Context, songId int64, verse *models.Verse) error {
+	return vs.repo.Insert(ctx, songId, verse)
+}
+
+func (vs *VerseService) UpdateVerse(ctx context.Context, songId int64, id int64, verse *models.Verse) (*models.Verse, error) {
+	return vs.repo.Update(ctx, songId, id, verse)
+}
+
+func (vs *VerseService) MoveVerse(ctx context.Context, songId int64, id int64, position int) error {
+	return vs.repo.Move(ctx, songId, id, position)
+}
+
+func (vs *VerseService) DeleteVerse(ctx context.Context, songId int64, id int64) error {
+	return vs.repo.Delete(ctx, songId, id)
+}
@@ -0,0 +1,9 @@
+drop table enrichment_job;
+
+update song set release_date = created_at::date where release_date is null;
+
+alter table song
+  drop column enrichment_status,
+  alter column release_date set not null,
+  alter column "text" drop default,
+  alter column "link" drop default;
@@ -0,0 +1,25 @@
+alter table song
+  add column enrichment_status text not null default 'completed'
+    check (enrichment_status in ('pending', 'completed', 'failed')),
+  alter column release_date drop not null,
+  alter column "text" set default '',
+  alter column "link" set default '';
+
+alter table song alter column enrichment_status set default 'pending';
+
+create table enrichment_job (
+  id bigserial primary key,
+  song_id bigint not null references song (id) on delete cascade,
+  status text not null default 'queued'
+    check (status in ('queued', 'processing', 'done', 'dead')),
+  attempts int not null default 0,
+  last_error text,
+  run_at timestamptz not null default now(),
+  locked_at timestamptz,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+create index enrichment_job_queued_run_at_idx on enrichment_job (run_at) where status = 'queued';
+create index enrichment_job_processing_locked_at_idx on enrichment_job (locked_at) where status = 'processing';
+create index enrichment_job_song_id_idx on enrichment_job (song_id);
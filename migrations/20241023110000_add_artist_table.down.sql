@@ -0,0 +1,5 @@
+alter table song drop column artist_id;
+
+drop table artist;
+
+drop function normalize_name(text);
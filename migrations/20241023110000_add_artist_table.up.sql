@@ -0,0 +1,30 @@
+create function normalize_name(name text) returns text
+  language sql immutable
+  as $$ select lower(btrim(regexp_replace(name, '\s+', ' ', 'g'))) $$;
+
+create table artist (
+  id bigserial primary key,
+  name text not null,
+  normalized_name text not null unique,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+-- каноническим именем артиста становится написание из самой ранней песни
+insert into artist (name, normalized_name)
+select distinct on (normalize_name("group"))
+  btrim(regexp_replace("group", '\s+', ' ', 'g')),
+  normalize_name("group")
+from song
+order by normalize_name("group"), created_at asc, id asc;
+
+alter table song add column artist_id bigint references artist (id);
+
+update song
+set artist_id = artist.id, "group" = artist.name
+from artist
+where artist.normalized_name = normalize_name(song."group");
+
+alter table song alter column artist_id set not null;
+
+create index song_artist_id_idx on song (artist_id);
@@ -0,0 +1,7 @@
+alter table song
+  drop constraint song_album_track_check,
+  drop column track_number,
+  drop column disc_number,
+  drop column album_id;
+
+drop table album;
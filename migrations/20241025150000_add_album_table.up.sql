@@ -0,0 +1,23 @@
+create table album (
+  id bigserial primary key,
+  title text not null,
+  artist_id bigint not null references artist (id),
+  release_date date,
+  cover_link text not null default '',
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+create index album_artist_id_idx on album (artist_id);
+
+alter table song
+  add column album_id bigint references album (id),
+  add column disc_number int check (disc_number > 0),
+  add column track_number int check (track_number > 0),
+  add constraint song_album_track_check check (
+    (album_id is null and disc_number is null and track_number is null)
+    or (album_id is not null and disc_number is not null and track_number is not null)
+  );
+
+create unique index song_album_disc_track_idx on song (album_id, disc_number, track_number)
+  where album_id is not null;
@@ -0,0 +1,3 @@
+drop table playlist_entry;
+
+drop table playlist;
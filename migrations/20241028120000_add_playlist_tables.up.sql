@@ -0,0 +1,18 @@
+create table playlist (
+  id bigserial primary key,
+  name text not null,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+create table playlist_entry (
+  id bigserial primary key,
+  playlist_id bigint not null references playlist (id) on delete cascade,
+  song_id bigint not null references song (id) on delete cascade,
+  position int not null check (position > 0),
+  added_at timestamptz not null default now(),
+  -- deferrable, чтобы сдвигать позиции внутри транзакции без временных конфликтов
+  constraint playlist_entry_position_key unique (playlist_id, position) deferrable initially immediate
+);
+
+create index playlist_entry_song_id_idx on playlist_entry (song_id);
@@ -0,0 +1,7 @@
+drop table song_tag;
+
+drop table song_genre;
+
+drop table tag;
+
+drop table genre;
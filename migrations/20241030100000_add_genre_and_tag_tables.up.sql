@@ -0,0 +1,35 @@
+create table genre (
+  id bigserial primary key,
+  name text not null,
+  normalized_name text not null unique,
+  -- поджанр нельзя оставить без родителя, сначала нужно удалить или перенести поджанры
+  parent_id bigint references genre (id) on delete restrict,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now(),
+  check (parent_id <> id)
+);
+
+create index genre_parent_id_idx on genre (parent_id);
+
+create table tag (
+  id bigserial primary key,
+  name text not null,
+  normalized_name text not null unique,
+  created_at timestamptz not null default now()
+);
+
+create table song_genre (
+  song_id bigint not null references song (id) on delete cascade,
+  genre_id bigint not null references genre (id) on delete cascade,
+  primary key (song_id, genre_id)
+);
+
+create index song_genre_genre_id_idx on song_genre (genre_id);
+
+create table song_tag (
+  song_id bigint not null references song (id) on delete cascade,
+  tag_id bigint not null references tag (id) on delete cascade,
+  primary key (song_id, tag_id)
+);
+
+create index song_tag_tag_id_idx on song_tag (tag_id);
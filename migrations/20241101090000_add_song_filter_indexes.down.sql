@@ -0,0 +1,5 @@
+drop index song_normalized_song_idx;
+
+drop index song_updated_at_idx;
+
+drop index song_created_at_idx;
@@ -0,0 +1,5 @@
+create index song_created_at_idx on song (created_at);
+
+create index song_updated_at_idx on song (updated_at);
+
+create index song_normalized_song_idx on song (normalize_name(song));
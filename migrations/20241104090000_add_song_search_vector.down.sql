@@ -0,0 +1,5 @@
+drop index song_search_vector_idx;
+
+alter table song drop column search_vector;
+
+alter table song drop column search_config;
@@ -0,0 +1,12 @@
+-- конфигурация полнотекстового поиска, с которой проиндексирована песня.
+-- По умолчанию берется из default_text_search_config соединения, который приложение задает из SEARCH_LANGUAGE
+alter table song add column search_config regconfig not null
+  default current_setting('default_text_search_config')::regconfig;
+
+alter table song add column search_vector tsvector generated always as (
+  setweight(to_tsvector(search_config, song), 'A') ||
+  setweight(to_tsvector(search_config, "group"), 'B') ||
+  setweight(to_tsvector(search_config, "text"), 'C')
+) stored;
+
+create index song_search_vector_idx on song using gin (search_vector);
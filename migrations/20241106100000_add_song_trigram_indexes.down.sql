@@ -0,0 +1,5 @@
+drop index song_group_trgm_idx;
+
+drop index song_song_trgm_idx;
+
+drop extension if exists pg_trgm;
@@ -0,0 +1,5 @@
+create extension if not exists pg_trgm;
+
+create index song_song_trgm_idx on song using gin (normalize_name(song) gin_trgm_ops);
+
+create index song_group_trgm_idx on song using gin (normalize_name("group") gin_trgm_ops);
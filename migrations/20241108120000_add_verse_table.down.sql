@@ -0,0 +1,4 @@
+-- возвращаем экранированные переводы строк, на которых была построена пагинация по куплетам
+update song set "text" = replace("text", E'\n', '\n');
+
+drop table verse;
@@ -0,0 +1,46 @@
+create table verse (
+  id bigserial primary key,
+  song_id bigint not null references song (id) on delete cascade,
+  position int not null check (position > 0),
+  kind text not null default 'verse' check (kind in ('verse', 'chorus', 'bridge')),
+  "text" text not null check ("text" <> ''),
+  -- deferrable, чтобы сдвигать позиции внутри транзакции без временных конфликтов
+  constraint verse_position_key unique (song_id, position) deferrable initially immediate
+);
+
+-- существующие тексты делятся на куплеты так же, как pkg/lyrics: экранированные и windows-переводы строк
+-- приводятся к \n, куплеты разделяются пустыми строками, первая строка вида [Chorus] задает тип куплета
+with normalized as (
+  select id, btrim(
+    regexp_replace(
+      replace(replace(replace(replace(replace("text", '\r\n', E'\n'), '\n', E'\n'), '\r', E'\n'), E'\r\n', E'\n'), E'\r', E'\n'),
+      '[ \t]+(\n|$)', '\1', 'g'
+    ),
+    E'\n'
+  ) as "text"
+  from song
+),
+blocks as (
+  select n.id as song_id, b.ord, b.block,
+    lower(substring(b.block from '(?i)^[ \t]*\[[ \t]*(verse|куплет|chorus|refrain|припев|bridge|бридж)[ \t]*[0-9]*[ \t]*\][ \t]*(?:\n|$)')) as marker
+  from normalized n, regexp_split_to_table(n."text", '\n{2,}') with ordinality as b(block, ord)
+),
+parsed as (
+  select song_id, ord,
+    case
+      when marker in ('chorus', 'refrain', 'припев') then 'chorus'
+      when marker in ('bridge', 'бридж') then 'bridge'
+      else 'verse'
+    end as kind,
+    btrim(case when marker is null then block else regexp_replace(block, '^[^\n]*\n?', '') end, E'\n') as "text"
+  from blocks
+)
+insert into verse (song_id, position, kind, "text")
+select song_id, row_number() over (partition by song_id order by ord), kind, "text"
+from parsed
+where btrim("text", E' \t\n') <> '';
+
+update song
+set "text" = coalesce((
+  select string_agg(v."text", E'\n\n' order by v.position) from verse v where v.song_id = song.id
+), '');
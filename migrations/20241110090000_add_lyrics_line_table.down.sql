@@ -0,0 +1 @@
+drop table lyrics_line;
@@ -0,0 +1,9 @@
+-- строки синхронизированного текста (LRC); текст песни и куплеты собираются из строк при загрузке
+create table lyrics_line (
+  id bigserial primary key,
+  song_id bigint not null references song (id) on delete cascade,
+  position int not null check (position > 0),
+  time_ms bigint not null check (time_ms >= 0),
+  "text" text not null,
+  constraint lyrics_line_position_key unique (song_id, position)
+);
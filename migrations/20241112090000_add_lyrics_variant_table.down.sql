@@ -0,0 +1,3 @@
+drop table lyrics_variant;
+
+alter table song drop column lang;
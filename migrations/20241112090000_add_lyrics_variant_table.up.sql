@@ -0,0 +1,19 @@
+-- язык оригинального текста песни, тег BCP 47; null - язык не указан
+alter table song add column lang text check (lang <> '');
+
+-- переводы и транслитерации текста песни, не больше одного варианта на язык
+create table lyrics_variant (
+  id bigserial primary key,
+  song_id bigint not null references song (id) on delete cascade,
+  lang text not null check (lang <> ''),
+  kind text not null default 'translation' check (kind in ('translation', 'transliteration')),
+  "text" text not null check ("text" <> ''),
+  -- конфигурация поиска выбирается по языку при записи варианта
+  search_config regconfig not null default 'simple',
+  search_vector tsvector generated always as (to_tsvector(search_config, "text")) stored,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now(),
+  constraint lyrics_variant_lang_key unique (song_id, lang)
+);
+
+create index lyrics_variant_search_vector_idx on lyrics_variant using gin (search_vector);
@@ -0,0 +1 @@
+drop table song_revision;
@@ -0,0 +1,27 @@
+-- история правок песни: снимок редактируемых полей после каждой правки. Ссылки на song нет,
+-- чтобы история удаленной песни сохранялась
+create table song_revision (
+  id bigserial primary key,
+  song_id bigint not null,
+  revision int not null check (revision > 0),
+  action text not null check (action in ('create', 'update', 'delete', 'restore')),
+  actor text,
+  snapshot jsonb not null,
+  -- поля снимка, отличающиеся от предыдущей ревизии
+  changed text[] not null default '{}',
+  restored_from int,
+  created_at timestamptz not null default now(),
+  constraint song_revision_key unique (song_id, revision)
+);
+
+-- у существующих песен история начинается с их текущего состояния
+insert into song_revision (song_id, revision, action, snapshot, changed, created_at)
+select id, 1, 'create', jsonb_build_object(
+  'song', song,
+  'group', "group",
+  'text', "text",
+  'link', "link",
+  'release_date', release_date,
+  'lang', lang
+), array['group', 'lang', 'link', 'release_date', 'song', 'text'], updated_at
+from song;
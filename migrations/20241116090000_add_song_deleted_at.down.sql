@@ -0,0 +1,3 @@
+delete from song where deleted_at is not null;
+
+alter table song drop column deleted_at;
@@ -0,0 +1,4 @@
+-- удаленная песня попадает в корзину: строка остается до очистки корзины, но не видна в выборках
+alter table song add column deleted_at timestamptz;
+
+create index song_deleted_at_idx on song (deleted_at) where deleted_at is not null;
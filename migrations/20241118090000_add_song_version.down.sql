@@ -0,0 +1 @@
+alter table song drop column version;
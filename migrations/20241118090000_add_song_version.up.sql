@@ -0,0 +1,2 @@
+-- версия песни увеличивается при каждой правке, клиенты получают ее в ETag и передают в If-Match
+alter table song add column version int not null default 1 check (version > 0);
@@ -0,0 +1,3 @@
+drop table idempotency_key;
+
+drop index song_artist_title_key;
@@ -0,0 +1,57 @@
+-- у исполнителя не может быть двух песен с одинаковым названием без учета регистра и пробелов.
+-- Из существующих дублей остается самая ранняя песня, остальные переносятся в корзину, как при удалении через api:
+-- в историю каждой перенесенной песни пишется ревизия delete с actor = 'migration'. Такие песни можно найти
+-- по этой ревизии и восстановить из корзины, пока не истек срок хранения. Откат миграции их не восстанавливает
+with duplicate as (
+  select id from (
+    select id, row_number() over (
+      partition by artist_id, normalize_name(song)
+      order by created_at asc, id asc
+    ) as n
+    from song
+    where deleted_at is null
+  ) d
+  where d.n > 1
+),
+trashed as (
+  update song s
+  set deleted_at = now(), version = s.version + 1
+  from duplicate
+  where s.id = duplicate.id
+  returning s.id, jsonb_build_object(
+    'song', s.song,
+    'group', s."group",
+    'text', s."text",
+    'link', s."link",
+    'release_date', s.release_date,
+    'lang', s.lang
+  ) as snapshot
+)
+insert into song_revision (song_id, revision, action, actor, snapshot, changed)
+select t.id, coalesce(prev.revision, 0) + 1, 'delete', 'migration', t.snapshot, array(
+  select c.key from jsonb_each(t.snapshot) c
+  where c.value is distinct from prev.snapshot -> c.key
+  order by c.key
+)
+from trashed t
+left join lateral (
+  select revision, snapshot from song_revision
+  where song_id = t.id
+  order by revision desc
+  limit 1
+) prev on true;
+
+create unique index song_artist_title_key on song (artist_id, normalize_name(song)) where deleted_at is null;
+
+-- ответы на запросы с заголовком Idempotency-Key. status null - запрос еще выполняется
+create table idempotency_key (
+  key text primary key,
+  -- хеш метода, пути и тела запроса: ключ нельзя использовать с другим запросом
+  fingerprint text not null,
+  status int,
+  headers jsonb not null default '{}',
+  body bytea,
+  created_at timestamptz not null default now()
+);
+
+create index idempotency_key_created_at_idx on idempotency_key (created_at);
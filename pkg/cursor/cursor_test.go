@@ -0,0 +1,91 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testPayload struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+func TestSignerRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload testPayload
+	}{
+		{name: "empty", payload: testPayload{}},
+		{name: "values", payload: testPayload{Sort: "-created_at", Values: []string{"2024-11-01T12:00:00Z", "42"}}},
+		{name: "unicode", payload: testPayload{Sort: "song", Values: []string{"Кино / Группа крови", "~.="}}},
+	}
+
+	signer := NewSigner([]byte("secret"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := signer.Encode(tt.payload)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			if strings.ContainsAny(token, "+/=") {
+				t.Errorf("Encode() = %q, want url-safe token without padding", token)
+			}
+
+			var decoded testPayload
+			if err := signer.Decode(token, &decoded); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(decoded, tt.payload) {
+				t.Errorf("Decode() = %+v, want %+v", decoded, tt.payload)
+			}
+		})
+	}
+}
+
+func TestSignerDecodeInvalid(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+
+	valid, err := signer.Encode(testPayload{Sort: "created_at", Values: []string{"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, signature, _ := strings.Cut(valid, ".")
+
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"song","v":["1"]}`))
+	otherSigner, err := NewSigner([]byte("other secret")).Encode(testPayload{Sort: "created_at", Values: []string{"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notJson := base64.RawURLEncoding.EncodeToString([]byte("not json"))
+	notJsonToken := notJson + "." + base64.RawURLEncoding.EncodeToString(signer.sign(notJson))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "without signature", token: encoded},
+		{name: "empty signature", token: encoded + "."},
+		{name: "signature is not base64", token: encoded + ".!!!"},
+		{name: "changed payload", token: forged + "." + signature},
+		{name: "changed signature", token: encoded + "." + base64.RawURLEncoding.EncodeToString([]byte("signature"))},
+		{name: "signed with another secret", token: otherSigner},
+		{name: "payload is not json", token: notJsonToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded testPayload
+			if err := signer.Decode(tt.token, &decoded); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode(%q) error = %v, want ErrInvalidCursor", tt.token, err)
+			}
+		})
+	}
+}
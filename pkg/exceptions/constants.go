@@ -1,14 +1,121 @@
 package exceptions
 
 const (
-	songIdIsNotProvidedErrorMsg         = "Song ID is not provided."
-	failedToParseSongIdErrorMsg         = "Failed to parse song ID. Invalid value passed."
-	songByIdNotFoundErrorMsg            = "Song with provided ID is not found."
-	songVerseNotFoundErrorMsg           = "Provided song verse is not found."
-	invalidPayloadToCreateASongErrorMsg = "Passed invalid payload to create a song."
-	fetchingSongsErrorMsg               = "Unknown error while fetching songs."
-	creatingSongErrorMsg                = "Unknown error while creating a song."
-	findSongDetailsErrorMsg             = "Unknown error while finding song details."
-	updatingSongErrorMsg                = "Unknown error while updating a song."
-	deletingSongErrorMsg                = "Unknown error while deleting a song by id."
+	songIdIsNotProvidedErrorMsg                    = "Song ID is not provided."
+	failedToParseSongIdErrorMsg                    = "Failed to parse song ID. Invalid value passed."
+	songByIdNotFoundErrorMsg                       = "Song with provided ID is not found."
+	songVerseNotFoundErrorMsg                      = "Provided song verse is not found."
+	invalidVerseSelectionErrorMsg                  = "Passed invalid verses selection. Use page and per_page or a verses range like 2-4."
+	invalidPayloadToCreateASongErrorMsg            = "Passed invalid payload to create a song."
+	fetchingSongsErrorMsg                          = "Unknown error while fetching songs."
+	creatingSongErrorMsg                           = "Unknown error while creating a song."
+	updatingSongErrorMsg                           = "Unknown error while updating a song."
+	deletingSongErrorMsg                           = "Unknown error while deleting a song by id."
+	invalidSongsFilterErrorMsg                     = "Passed invalid songs filter."
+	invalidSongsSortErrorMsg                       = "Passed invalid songs sort. Allowed fields: release_date, created_at, updated_at, song, group, relevance with search_query and similarity with fuzzy."
+	invalidSongsCursorErrorMsg                     = "Passed invalid songs cursor. Cursor must be used with the same sort it was issued for."
+	invalidSongSuggestQueryErrorMsg                = "Passed invalid suggest query. Query must not be empty."
+	fetchingSongSuggestionsErrorMsg                = "Unknown error while fetching song suggestions."
+	invalidLyricsSearchQueryErrorMsg               = "Passed invalid lyrics search query. Query must not be empty and distance must be from 1 to 100."
+	searchingLyricsErrorMsg                        = "Unknown error while searching lyrics."
+	unknownSongGenreErrorMsg                       = "Passed genre is not found. Create it before assigning to a song."
+	invalidPayloadToSaveSongClassificationErrorMsg = "Passed invalid payload to save song genres or tags."
+
+	artistIdIsNotProvidedErrorMsg        = "Artist ID is not provided."
+	failedToParseArtistIdErrorMsg        = "Failed to parse artist ID. Invalid value passed."
+	artistByIdNotFoundErrorMsg           = "Artist with provided ID is not found."
+	invalidPayloadToSaveAnArtistErrorMsg = "Passed invalid payload to save an artist."
+	artistAlreadyExistsErrorMsg          = "Artist with the same name already exists."
+	artistHasSongsErrorMsg               = "Artist has songs and can not be deleted."
+	fetchingArtistsErrorMsg              = "Unknown error while fetching artists."
+	creatingArtistErrorMsg               = "Unknown error while creating an artist."
+	updatingArtistErrorMsg               = "Unknown error while updating an artist."
+	deletingArtistErrorMsg               = "Unknown error while deleting an artist by id."
+
+	albumIdIsNotProvidedErrorMsg        = "Album ID is not provided."
+	failedToParseAlbumIdErrorMsg        = "Failed to parse album ID. Invalid value passed."
+	albumByIdNotFoundErrorMsg           = "Album with provided ID is not found."
+	invalidPayloadToSaveAnAlbumErrorMsg = "Passed invalid payload to save an album."
+	albumTrackNotFoundErrorMsg          = "Provided song is not found on the album."
+	albumTrackTakenErrorMsg             = "Provided disc and track number are already taken on the album."
+	fetchingAlbumsErrorMsg              = "Unknown error while fetching albums."
+	creatingAlbumErrorMsg               = "Unknown error while creating an album."
+	updatingAlbumErrorMsg               = "Unknown error while updating an album."
+	deletingAlbumErrorMsg               = "Unknown error while deleting an album by id."
+
+	playlistIdIsNotProvidedErrorMsg       = "Playlist ID is not provided."
+	failedToParsePlaylistIdErrorMsg       = "Failed to parse playlist ID. Invalid value passed."
+	playlistEntryIdIsNotProvidedErrorMsg  = "Playlist entry ID is not provided."
+	failedToParsePlaylistEntryIdErrorMsg  = "Failed to parse playlist entry ID. Invalid value passed."
+	playlistByIdNotFoundErrorMsg          = "Playlist with provided ID is not found."
+	playlistEntryNotFoundErrorMsg         = "Provided entry is not found in the playlist."
+	invalidPayloadToSaveAPlaylistErrorMsg = "Passed invalid payload to save a playlist."
+	fetchingPlaylistsErrorMsg             = "Unknown error while fetching playlists."
+	creatingPlaylistErrorMsg              = "Unknown error while creating a playlist."
+	updatingPlaylistErrorMsg              = "Unknown error while updating a playlist."
+	deletingPlaylistErrorMsg              = "Unknown error while deleting a playlist by id."
+
+	genreIdIsNotProvidedErrorMsg       = "Genre ID is not provided."
+	failedToParseGenreIdErrorMsg       = "Failed to parse genre ID. Invalid value passed."
+	genreByIdNotFoundErrorMsg          = "Genre with provided ID is not found."
+	invalidPayloadToSaveAGenreErrorMsg = "Passed invalid payload to save a genre."
+	genreAlreadyExistsErrorMsg         = "Genre with the same name already exists."
+	genreHasSubgenresErrorMsg          = "Genre has subgenres and can not be deleted."
+	genreParentNotFoundErrorMsg        = "Parent genre with provided ID is not found."
+	genreCycleErrorMsg                 = "Genre can not be moved under itself or its subgenre."
+	fetchingGenresErrorMsg             = "Unknown error while fetching genres."
+	creatingGenreErrorMsg              = "Unknown error while creating a genre."
+	updatingGenreErrorMsg              = "Unknown error while updating a genre."
+	deletingGenreErrorMsg              = "Unknown error while deleting a genre by id."
+
+	tagIdIsNotProvidedErrorMsg       = "Tag ID is not provided."
+	failedToParseTagIdErrorMsg       = "Failed to parse tag ID. Invalid value passed."
+	tagByIdNotFoundErrorMsg          = "Tag with provided ID is not found."
+	invalidPayloadToSaveATagErrorMsg = "Passed invalid payload to save a tag."
+	tagAlreadyExistsErrorMsg         = "Tag with the same name already exists."
+	fetchingTagsErrorMsg             = "Unknown error while fetching tags."
+	creatingTagErrorMsg              = "Unknown error while creating a tag."
+	deletingTagErrorMsg              = "Unknown error while deleting a tag by id."
+
+	verseIdIsNotProvidedErrorMsg       = "Verse ID is not provided."
+	failedToParseVerseIdErrorMsg       = "Failed to parse verse ID. Invalid value passed."
+	verseByIdNotFoundErrorMsg          = "Verse with provided ID is not found in the song."
+	invalidPayloadToSaveAVerseErrorMsg = "Passed invalid payload to save a verse. Text must be a single verse without blank lines, kind must be verse, chorus or bridge."
+	fetchingVersesErrorMsg             = "Unknown error while fetching song verses."
+	creatingVerseErrorMsg              = "Unknown error while creating a verse."
+	updatingVerseErrorMsg              = "Unknown error while updating a verse."
+	deletingVerseErrorMsg              = "Unknown error while deleting a verse by id."
+
+	invalidLyricsFormatErrorMsg               = "Passed invalid lyrics format. Supported formats: lrc, json, plain."
+	invalidLrcFileErrorMsg                    = "Passed invalid LRC file. Every line needs a [mm:ss.xx] timestamp and timestamps must increase."
+	syncedLyricsNotFoundErrorMsg              = "Song has no synced lyrics."
+	fetchingLyricsErrorMsg                    = "Unknown error while fetching song lyrics."
+	savingLyricsErrorMsg                      = "Unknown error while saving song lyrics."
+	invalidLanguageErrorMsg                   = "Passed invalid language. Use a BCP 47 tag like en or ru-Latn."
+	invalidPayloadToSaveLyricsVariantErrorMsg = "Passed invalid payload to save a lyrics variant."
+	lyricsVariantNotFoundErrorMsg             = "Lyrics variant in the provided language is not found."
+	deletingLyricsVariantErrorMsg             = "Unknown error while deleting a lyrics variant."
+	failedToParseRevisionErrorMsg             = "Failed to parse revision number."
+	songRevisionNotFoundErrorMsg              = "Song revision is not found."
+	fetchingSongRevisionsErrorMsg             = "Unknown error while fetching song revisions."
+	restoringSongRevisionErrorMsg             = "Unknown error while restoring a song revision."
+	songNotInTrashErrorMsg                    = "Song with provided ID is not found in trash."
+	fetchingTrashErrorMsg                     = "Unknown error while fetching trashed songs."
+	restoringSongFromTrashErrorMsg            = "Unknown error while restoring a song from trash."
+	songVersionMismatchErrorMsg               = "Song was modified by another request, If-Match does not match its current version."
+	ifMatchRequiredErrorMsg                   = "If-Match header with the song ETag is required."
+	unsupportedSongPatchTypeErrorMsg          = "Unsupported patch content type, use application/merge-patch+json or application/json-patch+json."
+	invalidSongPatchErrorMsg                  = "Passed invalid patch document."
+	songPatchTestFailedErrorMsg               = "Song patch test operation failed."
+	unprocessableSongPatchErrorMsg            = "Song patch can not be applied, a path is not found."
+	invalidSongDocumentErrorMsg               = "Passed invalid song document: unknown field or a value of the wrong type."
+	invalidPayloadToReplaceSongErrorMsg       = "Passed invalid payload to replace a song."
+	validationFailedErrorMsg                  = "Passed payload failed validation, see details for the offending fields."
+	songAlreadyExistsErrorMsg                 = "Song with the same artist and title already exists, see Location for the existing song."
+	invalidIdempotencyKeyErrorMsg             = "Passed invalid Idempotency-Key, it must be 1 to 255 printable ASCII characters."
+	idempotencyKeyReusedErrorMsg              = "Idempotency-Key is already used with a different request."
+	idempotencyKeyInProgressErrorMsg          = "Request with this Idempotency-Key is still in progress, retry later."
+	idempotencyErrorMsg                       = "Unknown error while processing Idempotency-Key."
+	requestBodyTooLargeErrorMsg               = "Request body is too large."
+	invalidPaginationErrorMsg                 = "Passed invalid pagination, limit and page must be positive integers."
 )
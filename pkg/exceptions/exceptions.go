@@ -55,23 +55,724 @@ func CreatingSongError(c *gin.Context) {
 	})
 }
 
-func FindSongDetailsError(c *gin.Context) {
+func UpdatingSongError(c *gin.Context) {
 	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
 		Code:    http.StatusInternalServerError,
-		Message: findSongDetailsErrorMsg,
+		Message: updatingSongErrorMsg,
 	})
 }
 
-func UpdatingSongError(c *gin.Context) {
+func DeletingSongError(c *gin.Context) {
 	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
 		Code:    http.StatusInternalServerError,
-		Message: updatingSongErrorMsg,
+		Message: deletingSongErrorMsg,
 	})
 }
 
-func DeletingSongError(c *gin.Context) {
+func ArtistIdIsNotProvidedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: artistIdIsNotProvidedErrorMsg,
+	})
+}
+
+func FailedToParseArtistIdError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: failedToParseArtistIdErrorMsg,
+	})
+}
+
+func ArtistByIdNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: artistByIdNotFoundErrorMsg,
+	})
+}
+
+func InvalidPayloadToSaveAnArtistError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToSaveAnArtistErrorMsg,
+	})
+}
+
+func ArtistAlreadyExistsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: artistAlreadyExistsErrorMsg,
+	})
+}
+
+func ArtistHasSongsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: artistHasSongsErrorMsg,
+	})
+}
+
+func FetchingArtistsError(c *gin.Context) {
 	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
 		Code:    http.StatusInternalServerError,
-		Message: deletingSongErrorMsg,
+		Message: fetchingArtistsErrorMsg,
+	})
+}
+
+func CreatingArtistError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: creatingArtistErrorMsg,
+	})
+}
+
+func UpdatingArtistError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: updatingArtistErrorMsg,
+	})
+}
+
+func DeletingArtistError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: deletingArtistErrorMsg,
+	})
+}
+
+func AlbumIdIsNotProvidedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: albumIdIsNotProvidedErrorMsg,
+	})
+}
+
+func FailedToParseAlbumIdError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: failedToParseAlbumIdErrorMsg,
+	})
+}
+
+func AlbumByIdNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: albumByIdNotFoundErrorMsg,
+	})
+}
+
+func InvalidPayloadToSaveAnAlbumError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToSaveAnAlbumErrorMsg,
+	})
+}
+
+func AlbumTrackNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: albumTrackNotFoundErrorMsg,
+	})
+}
+
+func AlbumTrackTakenError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: albumTrackTakenErrorMsg,
+	})
+}
+
+func FetchingAlbumsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingAlbumsErrorMsg,
+	})
+}
+
+func CreatingAlbumError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: creatingAlbumErrorMsg,
+	})
+}
+
+func UpdatingAlbumError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: updatingAlbumErrorMsg,
+	})
+}
+
+func DeletingAlbumError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: deletingAlbumErrorMsg,
+	})
+}
+
+func PlaylistIdIsNotProvidedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: playlistIdIsNotProvidedErrorMsg,
+	})
+}
+
+func FailedToParsePlaylistIdError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: failedToParsePlaylistIdErrorMsg,
+	})
+}
+
+func PlaylistEntryIdIsNotProvidedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: playlistEntryIdIsNotProvidedErrorMsg,
+	})
+}
+
+func FailedToParsePlaylistEntryIdError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: failedToParsePlaylistEntryIdErrorMsg,
+	})
+}
+
+func PlaylistByIdNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: playlistByIdNotFoundErrorMsg,
+	})
+}
+
+func PlaylistEntryNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: playlistEntryNotFoundErrorMsg,
+	})
+}
+
+func InvalidPayloadToSaveAPlaylistError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToSaveAPlaylistErrorMsg,
+	})
+}
+
+func FetchingPlaylistsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingPlaylistsErrorMsg,
+	})
+}
+
+func CreatingPlaylistError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: creatingPlaylistErrorMsg,
+	})
+}
+
+func UpdatingPlaylistError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: updatingPlaylistErrorMsg,
+	})
+}
+
+func DeletingPlaylistError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: deletingPlaylistErrorMsg,
+	})
+}
+
+func InvalidSongsFilterError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidSongsFilterErrorMsg,
+	})
+}
+
+func UnknownSongGenreError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: unknownSongGenreErrorMsg,
+	})
+}
+
+func InvalidPayloadToSaveSongClassificationError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToSaveSongClassificationErrorMsg,
+	})
+}
+
+func GenreIdIsNotProvidedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: genreIdIsNotProvidedErrorMsg,
+	})
+}
+
+func FailedToParseGenreIdError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: failedToParseGenreIdErrorMsg,
+	})
+}
+
+func GenreByIdNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: genreByIdNotFoundErrorMsg,
+	})
+}
+
+func InvalidPayloadToSaveAGenreError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToSaveAGenreErrorMsg,
+	})
+}
+
+func GenreAlreadyExistsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: genreAlreadyExistsErrorMsg,
+	})
+}
+
+func GenreHasSubgenresError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: genreHasSubgenresErrorMsg,
+	})
+}
+
+func GenreParentNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: genreParentNotFoundErrorMsg,
+	})
+}
+
+func GenreCycleError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: genreCycleErrorMsg,
+	})
+}
+
+func FetchingGenresError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingGenresErrorMsg,
+	})
+}
+
+func CreatingGenreError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: creatingGenreErrorMsg,
+	})
+}
+
+func UpdatingGenreError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: updatingGenreErrorMsg,
+	})
+}
+
+func DeletingGenreError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: deletingGenreErrorMsg,
+	})
+}
+
+func TagIdIsNotProvidedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: tagIdIsNotProvidedErrorMsg,
+	})
+}
+
+func FailedToParseTagIdError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: failedToParseTagIdErrorMsg,
+	})
+}
+
+func TagByIdNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: tagByIdNotFoundErrorMsg,
+	})
+}
+
+func InvalidPayloadToSaveATagError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToSaveATagErrorMsg,
+	})
+}
+
+func TagAlreadyExistsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: tagAlreadyExistsErrorMsg,
+	})
+}
+
+func FetchingTagsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingTagsErrorMsg,
+	})
+}
+
+func CreatingTagError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: creatingTagErrorMsg,
+	})
+}
+
+func DeletingTagError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: deletingTagErrorMsg,
+	})
+}
+
+func InvalidSongsSortError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidSongsSortErrorMsg,
+	})
+}
+
+func InvalidSongsCursorError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidSongsCursorErrorMsg,
+	})
+}
+
+func InvalidSongSuggestQueryError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidSongSuggestQueryErrorMsg,
+	})
+}
+
+func FetchingSongSuggestionsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingSongSuggestionsErrorMsg,
+	})
+}
+
+func InvalidLyricsSearchQueryError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidLyricsSearchQueryErrorMsg,
+	})
+}
+
+func SearchingLyricsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: searchingLyricsErrorMsg,
+	})
+}
+
+func VerseIdIsNotProvidedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: verseIdIsNotProvidedErrorMsg,
+	})
+}
+
+func FailedToParseVerseIdError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: failedToParseVerseIdErrorMsg,
+	})
+}
+
+func VerseByIdNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: verseByIdNotFoundErrorMsg,
+	})
+}
+
+func InvalidPayloadToSaveAVerseError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToSaveAVerseErrorMsg,
+	})
+}
+
+func FetchingVersesError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingVersesErrorMsg,
+	})
+}
+
+func CreatingVerseError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: creatingVerseErrorMsg,
+	})
+}
+
+func UpdatingVerseError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: updatingVerseErrorMsg,
+	})
+}
+
+func DeletingVerseError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: deletingVerseErrorMsg,
+	})
+}
+
+func InvalidVerseSelectionError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidVerseSelectionErrorMsg,
+	})
+}
+
+func InvalidLyricsFormatError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidLyricsFormatErrorMsg,
+	})
+}
+
+func InvalidLrcFileError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidLrcFileErrorMsg,
+	})
+}
+
+func SyncedLyricsNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: syncedLyricsNotFoundErrorMsg,
+	})
+}
+
+func FetchingLyricsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingLyricsErrorMsg,
+	})
+}
+
+func SavingLyricsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: savingLyricsErrorMsg,
+	})
+}
+
+func InvalidLanguageError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidLanguageErrorMsg,
+	})
+}
+
+func InvalidPayloadToSaveLyricsVariantError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToSaveLyricsVariantErrorMsg,
+	})
+}
+
+func LyricsVariantNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: lyricsVariantNotFoundErrorMsg,
+	})
+}
+
+func DeletingLyricsVariantError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: deletingLyricsVariantErrorMsg,
+	})
+}
+
+func FailedToParseRevisionError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: failedToParseRevisionErrorMsg,
+	})
+}
+
+func SongRevisionNotFoundError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: songRevisionNotFoundErrorMsg,
+	})
+}
+
+func FetchingSongRevisionsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingSongRevisionsErrorMsg,
+	})
+}
+
+func RestoringSongRevisionError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: restoringSongRevisionErrorMsg,
+	})
+}
+
+func SongNotInTrashError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: songNotInTrashErrorMsg,
+	})
+}
+
+func FetchingTrashError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: fetchingTrashErrorMsg,
+	})
+}
+
+func RestoringSongFromTrashError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: restoringSongFromTrashErrorMsg,
+	})
+}
+
+func SongVersionMismatchError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusPreconditionFailed, Error{
+		Code:    http.StatusPreconditionFailed,
+		Message: songVersionMismatchErrorMsg,
+	})
+}
+
+func IfMatchRequiredError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusPreconditionRequired, Error{
+		Code:    http.StatusPreconditionRequired,
+		Message: ifMatchRequiredErrorMsg,
+	})
+}
+
+func UnsupportedSongPatchTypeError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, Error{
+		Code:    http.StatusUnsupportedMediaType,
+		Message: unsupportedSongPatchTypeErrorMsg,
+	})
+}
+
+func InvalidSongPatchError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidSongPatchErrorMsg,
+	})
+}
+
+func SongPatchTestFailedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: songPatchTestFailedErrorMsg,
+	})
+}
+
+func UnprocessableSongPatchError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, Error{
+		Code:    http.StatusUnprocessableEntity,
+		Message: unprocessableSongPatchErrorMsg,
+	})
+}
+
+func InvalidSongDocumentError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, Error{
+		Code:    http.StatusUnprocessableEntity,
+		Message: invalidSongDocumentErrorMsg,
+	})
+}
+
+func InvalidPayloadToReplaceSongError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPayloadToReplaceSongErrorMsg,
+	})
+}
+
+func ValidationFailedError(c *gin.Context, details []ErrorDetail) {
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, Error{
+		Code:    http.StatusUnprocessableEntity,
+		Message: validationFailedErrorMsg,
+		Details: details,
+	})
+}
+
+func SongAlreadyExistsError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: songAlreadyExistsErrorMsg,
+	})
+}
+
+func InvalidIdempotencyKeyError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidIdempotencyKeyErrorMsg,
+	})
+}
+
+func IdempotencyKeyReusedError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, Error{
+		Code:    http.StatusUnprocessableEntity,
+		Message: idempotencyKeyReusedErrorMsg,
+	})
+}
+
+func IdempotencyKeyInProgressError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusConflict, Error{
+		Code:    http.StatusConflict,
+		Message: idempotencyKeyInProgressErrorMsg,
+	})
+}
+
+func IdempotencyError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusInternalServerError, Error{
+		Code:    http.StatusInternalServerError,
+		Message: idempotencyErrorMsg,
+	})
+}
+
+func RequestBodyTooLargeError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, Error{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: requestBodyTooLargeErrorMsg,
+	})
+}
+
+func InvalidPaginationError(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, Error{
+		Code:    http.StatusBadRequest,
+		Message: invalidPaginationErrorMsg,
 	})
 }
@@ -3,4 +3,13 @@ package exceptions
 type Error struct {
 	Message string `json:"message"`
 	Code    int    `json:"code"`
+	// нарушения в отдельных полях запроса, заполняются только при ошибке валидации
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail - нарушение в поле запроса: code - машиночитаемый код правила, message - описание для человека
+type ErrorDetail struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,431 @@
+// Package jsonpatch применяет к JSON-документам JSON Merge Patch (RFC 7396) и JSON Patch (RFC 6902).
+// Документ и патч разбираются целиком в память, числа сохраняют исходную запись.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	// патч не разобран: некорректный JSON, неизвестная операция, неправильный JSON Pointer
+	ErrInvalidPatch = errors.New("invalid patch")
+	// путь операции не существует в документе
+	ErrPathNotFound = errors.New("patch path is not found")
+	// операция test не совпала с документом
+	ErrTestFailed = errors.New("patch test failed")
+)
+
+// MergePatch применяет JSON Merge Patch: объекты сливаются рекурсивно, null удаляет поле, остальные значения заменяются
+func MergePatch(doc []byte, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+// Apply применяет операции JSON Patch по порядку. При ошибке любой операции документ не меняется,
+// ошибка содержит номер операции
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []map[string]json.RawMessage
+	err = json.Unmarshal(patch, &operations)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+	}
+
+	for i, raw := range operations {
+		target, err = applyOperation(target, raw)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func merge(target any, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+
+		t[key] = merge(t[key], value)
+	}
+
+	return t
+}
+
+func applyOperation(doc any, raw map[string]json.RawMessage) (any, error) {
+	var op string
+	if err := json.Unmarshal(raw["op"], &op); err != nil {
+		return nil, fmt.Errorf("%w: op is required", ErrInvalidPatch)
+	}
+
+	path, err := pointerMember(raw, "path")
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "add", "replace", "test":
+		rawValue, ok := raw["value"]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s requires value", ErrInvalidPatch, op)
+		}
+
+		value, err := decode(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err)
+		}
+
+		switch op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+
+			if !equal(current, value) {
+				return nil, fmt.Errorf("%w: %s", ErrTestFailed, formatPointer(path))
+			}
+
+			return doc, nil
+		}
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "move", "copy":
+		from, err := pointerMember(raw, "from")
+		if err != nil {
+			return nil, err
+		}
+
+		if op == "move" {
+			// значение нельзя переместить внутрь него самого
+			if len(from) < len(path) && isPrefix(from, path) {
+				return nil, fmt.Errorf("%w: move into itself", ErrInvalidPatch)
+			}
+
+			doc, value, err := remove(doc, from)
+			if err != nil {
+				return nil, err
+			}
+
+			return add(doc, path, value)
+		}
+
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return add(doc, path, deepCopy(value))
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op)
+	}
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parentPath, key := path[:len(path)-1], path[len(path)-1]
+	parent, err := get(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := parent.(type) {
+	case map[string]any:
+		p[key] = value
+		return doc, nil
+	case []any:
+		i := len(p)
+		if key != "-" {
+			i, err = index(key, len(p)+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		inserted := make([]any, 0, len(p)+1)
+		inserted = append(append(append(inserted, p[:i]...), value), p[i:]...)
+		return set(doc, parentPath, inserted)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPointer(path))
+	}
+}
+
+func replace(doc any, path []string, value any) (any, error) {
+	_, err := get(doc, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return set(doc, path, value)
+}
+
+func remove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: can not remove the whole document", ErrInvalidPatch)
+	}
+
+	value, err := get(doc, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parentPath, key := path[:len(path)-1], path[len(path)-1]
+	parent, _ := get(doc, parentPath)
+	switch p := parent.(type) {
+	case map[string]any:
+		delete(p, key)
+		return doc, value, nil
+	case []any:
+		// индекс уже проверен в get
+		i, _ := index(key, len(p))
+		removed := make([]any, 0, len(p)-1)
+		removed = append(append(removed, p[:i]...), p[i+1:]...)
+		doc, err = set(doc, parentPath, removed)
+		return doc, value, err
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPointer(path))
+}
+
+// set заменяет существующее значение по пути
+func set(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parentPath, key := path[:len(path)-1], path[len(path)-1]
+	parent, err := get(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := parent.(type) {
+	case map[string]any:
+		p[key] = value
+	case []any:
+		i, err := index(key, len(p))
+		if err != nil {
+			return nil, err
+		}
+
+		// срез делит массив с родителем, поэтому запись видна в документе
+		p[i] = value
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPointer(path))
+	}
+
+	return doc, nil
+}
+
+func get(doc any, path []string) (any, error) {
+	current := doc
+	for n, key := range path {
+		switch c := current.(type) {
+		case map[string]any:
+			value, ok := c[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPointer(path[:n+1]))
+			}
+
+			current = value
+		case []any:
+			i, err := index(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+
+			current = c[i]
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, formatPointer(path[:n+1]))
+		}
+	}
+
+	return current, nil
+}
+
+// index разбирает индекс массива: десятичное число без ведущих нулей меньше limit
+func index(key string, limit int) (int, error) {
+	if key == "" || (len(key) > 1 && key[0] == '0') || strings.TrimLeft(key, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, key)
+	}
+
+	i, err := strconv.Atoi(key)
+	if err != nil || i >= limit {
+		return 0, fmt.Errorf("%w: array index %s is out of range", ErrPathNotFound, key)
+	}
+
+	return i, nil
+}
+
+// pointerMember разбирает JSON Pointer из поля операции: "/a/b~1c" - ключи "a" и "b/c", "" - весь документ
+func pointerMember(raw map[string]json.RawMessage, name string) ([]string, error) {
+	var pointer string
+	if err := json.Unmarshal(raw[name], &pointer); err != nil {
+		return nil, fmt.Errorf("%w: %s is required", ErrInvalidPatch, name)
+	}
+
+	if pointer == "" {
+		return []string{}, nil
+	}
+
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("%w: %s %q must start with /", ErrInvalidPatch, name, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if strings.Contains(strings.NewReplacer("~0", "", "~1", "").Replace(token), "~") {
+			return nil, fmt.Errorf("%w: invalid escape in %s %q", ErrInvalidPatch, name, pointer)
+		}
+
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+func formatPointer(path []string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+
+	var b strings.Builder
+	for _, key := range path {
+		b.WriteString("/" + escaper.Replace(key))
+	}
+
+	return b.String()
+}
+
+func isPrefix(prefix []string, path []string) bool {
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equal сравнивает значения как JSON: числа по значению, объекты без учета порядка полей
+func equal(a any, b any) bool {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+
+		return true
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+
+		af, aErr := av.Float64()
+		bf, bErr := bv.Float64()
+		if aErr != nil || bErr != nil {
+			return av == bv
+		}
+
+		return af == bf
+	default:
+		return a == b
+	}
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+
+		return copied
+	default:
+		return v
+	}
+}
+
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after json value")
+	}
+
+	return value, nil
+}
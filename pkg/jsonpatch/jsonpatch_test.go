@@ -0,0 +1,166 @@
+package jsonpatch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// assertJSONEqual сравнивает документы без учета порядка полей и записи чисел
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	g, err := decode(got)
+	if err != nil {
+		t.Fatalf("result is not json: %s", got)
+	}
+
+	w, err := decode([]byte(want))
+	if err != nil {
+		t.Fatalf("want is not json: %s", want)
+	}
+
+	if !equal(g, w) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{name: "replace field", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add field", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "null removes field", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "array is replaced", doc: `{"a":[1,2]}`, patch: `{"a":[3]}`, want: `{"a":[3]}`},
+		{name: "nested merge", doc: `{"a":{"b":"c","d":"e"}}`, patch: `{"a":{"d":null,"f":"g"}}`, want: `{"a":{"b":"c","f":"g"}}`},
+		{name: "object replaces scalar", doc: `{"a":"b"}`, patch: `{"a":{"c":null,"d":1}}`, want: `{"a":{"d":1}}`},
+		{name: "non-object patch replaces document", doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{name: "numbers keep precision", doc: `{"a":1}`, patch: `{"b":12345678901234567890}`, want: `{"a":1,"b":12345678901234567890}`},
+		{name: "invalid patch", doc: `{}`, patch: `{"a":`, wantErr: ErrInvalidPatch},
+		{name: "trailing data in patch", doc: `{}`, patch: `{} {}`, wantErr: ErrInvalidPatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("MergePatch() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("MergePatch() error = %v", err)
+			}
+
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	const doc = `{"song":"Uprising","group":"Muse","tags":["rock","alt"],"a/b":{"~c":1}}`
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "replace",
+			patch: `[{"op":"replace","path":"/song","value":"Madness"}]`,
+			want:  `{"song":"Madness","group":"Muse","tags":["rock","alt"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:  "add field",
+			patch: `[{"op":"add","path":"/link","value":"https://example.com"}]`,
+			want:  `{"song":"Uprising","group":"Muse","tags":["rock","alt"],"a/b":{"~c":1},"link":"https://example.com"}`,
+		},
+		{
+			name:  "add to array by index",
+			patch: `[{"op":"add","path":"/tags/1","value":"live"}]`,
+			want:  `{"song":"Uprising","group":"Muse","tags":["rock","live","alt"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:  "append to array",
+			patch: `[{"op":"add","path":"/tags/-","value":"live"}]`,
+			want:  `{"song":"Uprising","group":"Muse","tags":["rock","alt","live"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:  "remove from array",
+			patch: `[{"op":"remove","path":"/tags/0"}]`,
+			want:  `{"song":"Uprising","group":"Muse","tags":["alt"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:  "escaped pointer",
+			patch: `[{"op":"replace","path":"/a~1b/~0c","value":2}]`,
+			want:  `{"song":"Uprising","group":"Muse","tags":["rock","alt"],"a/b":{"~c":2}}`,
+		},
+		{
+			name:  "move",
+			patch: `[{"op":"move","from":"/group","path":"/artist"}]`,
+			want:  `{"song":"Uprising","artist":"Muse","tags":["rock","alt"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:  "copy is deep",
+			patch: `[{"op":"copy","from":"/tags","path":"/genres"},{"op":"add","path":"/genres/-","value":"pop"}]`,
+			want:  `{"song":"Uprising","group":"Muse","tags":["rock","alt"],"genres":["rock","alt","pop"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:  "test passes",
+			patch: `[{"op":"test","path":"/a~1b","value":{"~c":1.0}},{"op":"remove","path":"/a~1b"}]`,
+			want:  `{"song":"Uprising","group":"Muse","tags":["rock","alt"]}`,
+		},
+		{
+			name:  "replace whole document",
+			patch: `[{"op":"replace","path":"","value":{"song":"Madness"}}]`,
+			want:  `{"song":"Madness"}`,
+		},
+		{name: "test fails", patch: `[{"op":"test","path":"/song","value":"Madness"}]`, wantErr: ErrTestFailed},
+		{name: "replace missing field", patch: `[{"op":"replace","path":"/link","value":"x"}]`, wantErr: ErrPathNotFound},
+		{name: "remove missing field", patch: `[{"op":"remove","path":"/link"}]`, wantErr: ErrPathNotFound},
+		{name: "add past array end", patch: `[{"op":"add","path":"/tags/3","value":"x"}]`, wantErr: ErrPathNotFound},
+		{name: "array index with leading zero", patch: `[{"op":"remove","path":"/tags/01"}]`, wantErr: ErrInvalidPatch},
+		{name: "move into itself", patch: `[{"op":"move","from":"/a~1b","path":"/a~1b/d"}]`, wantErr: ErrInvalidPatch},
+		{name: "remove whole document", patch: `[{"op":"remove","path":""}]`, wantErr: ErrInvalidPatch},
+		{name: "unknown op", patch: `[{"op":"increment","path":"/song"}]`, wantErr: ErrInvalidPatch},
+		{name: "missing value", patch: `[{"op":"add","path":"/song"}]`, wantErr: ErrInvalidPatch},
+		{name: "path without slash", patch: `[{"op":"remove","path":"song"}]`, wantErr: ErrInvalidPatch},
+		{name: "invalid escape", patch: `[{"op":"remove","path":"/a~2"}]`, wantErr: ErrInvalidPatch},
+		{name: "patch is not an array", patch: `{"op":"remove","path":"/song"}`, wantErr: ErrInvalidPatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(doc), []byte(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyErrorNamesOperation(t *testing.T) {
+	patch := `[{"op":"replace","path":"/song","value":"Madness"},{"op":"remove","path":"/link"}]`
+
+	_, err := Apply([]byte(`{"song":"Uprising"}`), []byte(patch))
+	if !errors.Is(err, ErrPathNotFound) || !strings.HasPrefix(err.Error(), "operation 1: ") {
+		t.Fatalf("Apply() error = %v, want ErrPathNotFound of operation 1", err)
+	}
+}
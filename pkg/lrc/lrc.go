@@ -0,0 +1,170 @@
+// Package lrc разбирает и собирает тексты песен в формате LRC: строки с метками времени вида [01:23.45].
+// Строка может иметь несколько меток, метки слов <01:23.45> внутри строки отбрасываются,
+// из тегов учитывается только [offset:±ms].
+package lrc
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmpty         = errors.New("lrc has no timed lines")
+	ErrNoTimestamp   = errors.New("lrc line has no timestamp")
+	ErrBadTimestamp  = errors.New("invalid lrc timestamp")
+	ErrNotMonotonic  = errors.New("lrc timestamps are not increasing")
+	ErrInvalidOffset = errors.New("invalid lrc offset")
+)
+
+// maxLineTimestamps ограничивает количество меток у одной строки
+const maxLineTimestamps = 64
+
+var (
+	newlines       = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+	timestamp      = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	tag            = regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`)
+	wordTimestamp  = regexp.MustCompile(`<\d{1,3}:\d{1,2}(?:[.:]\d{1,3})?>`)
+	repeatedSpaces = regexp.MustCompile(`[ \t]+`)
+)
+
+// Line - строка текста и время, с которого она звучит. Пустой текст - пауза, в тексте песни она разделяет куплеты
+type Line struct {
+	Time time.Duration
+	Text string
+}
+
+// Tags - теги заголовка, которые записываются при сборке файла
+type Tags struct {
+	Artist string
+	Title  string
+	Album  string
+}
+
+// Parse разбирает LRC. Метки времени в порядке следования в файле должны строго возрастать,
+// ошибка содержит номер строки файла
+func Parse(data string) ([]Line, error) {
+	data = strings.TrimPrefix(newlines.Replace(data), "\ufeff")
+
+	lines := make([]Line, 0)
+	var offset time.Duration
+	for i, raw := range strings.Split(data, "\n") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		if match := tag.FindStringSubmatch(raw); match != nil {
+			if strings.EqualFold(match[1], "offset") {
+				ms, err := strconv.Atoi(strings.TrimSpace(match[2]))
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", i+1, ErrInvalidOffset)
+				}
+				offset = time.Duration(ms) * time.Millisecond
+			}
+			continue
+		}
+
+		times, text, err := parseLine(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		for _, t := range times {
+			if len(lines) > 0 && t <= lines[len(lines)-1].Time {
+				return nil, fmt.Errorf("line %d: %s after %s: %w", i+1, formatTime(t), formatTime(lines[len(lines)-1].Time), ErrNotMonotonic)
+			}
+
+			lines = append(lines, Line{Time: t, Text: text})
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrEmpty
+	}
+
+	// положительный offset показывает строки раньше
+	for i := range lines {
+		lines[i].Time = max(lines[i].Time-offset, 0)
+	}
+
+	return lines, nil
+}
+
+// Format собирает LRC из строк. Время округляется вниз до сотых секунды
+func Format(lines []Line, tags Tags) string {
+	var b strings.Builder
+	for _, header := range [][2]string{{"ar", tags.Artist}, {"ti", tags.Title}, {"al", tags.Album}} {
+		if header[1] != "" {
+			fmt.Fprintf(&b, "[%s:%s]\n", header[0], header[1])
+		}
+	}
+
+	for _, line := range lines {
+		fmt.Fprintf(&b, "[%s]%s\n", formatTime(line.Time), line.Text)
+	}
+
+	return b.String()
+}
+
+// Text собирает текст без меток времени: строка на строку, паузы - пустые строки
+func Text(lines []Line) string {
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		texts = append(texts, line.Text)
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+func parseLine(raw string) ([]time.Duration, string, error) {
+	times := make([]time.Duration, 0, 1)
+	for {
+		match := timestamp.FindStringSubmatch(raw)
+		if match == nil {
+			break
+		}
+
+		t, err := parseTime(match[1], match[2], match[3])
+		if err != nil {
+			return nil, "", err
+		}
+
+		times = append(times, t)
+		raw = raw[len(match[0]):]
+		if len(times) > maxLineTimestamps {
+			return nil, "", ErrBadTimestamp
+		}
+	}
+
+	if len(times) == 0 {
+		return nil, "", ErrNoTimestamp
+	}
+
+	text := wordTimestamp.ReplaceAllString(raw, "")
+	return times, strings.TrimSpace(repeatedSpaces.ReplaceAllString(text, " ")), nil
+}
+
+func parseTime(minutes string, seconds string, fraction string) (time.Duration, error) {
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(seconds)
+	if s >= 60 {
+		return 0, ErrBadTimestamp
+	}
+
+	// .5, .50 и .500 - одинаковые полсекунды
+	ms := 0
+	if fraction != "" {
+		ms, _ = strconv.Atoi((fraction + "00")[:3])
+	}
+
+	return time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+func formatTime(t time.Duration) string {
+	cs := t.Milliseconds() / 10
+	return fmt.Sprintf("%02d:%02d.%02d", cs/6000, cs/100%60, cs%100)
+}
@@ -0,0 +1,163 @@
+package lrc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ms(milliseconds int) time.Duration {
+	return time.Duration(milliseconds) * time.Millisecond
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Line
+		wantErr error
+	}{
+		{
+			name: "lines",
+			data: "[00:12.00]Ooh baby\n[00:15.5]Ooh\n[01:02.345]You set my soul alight",
+			want: []Line{
+				{Time: ms(12000), Text: "Ooh baby"},
+				{Time: ms(15500), Text: "Ooh"},
+				{Time: ms(62345), Text: "You set my soul alight"},
+			},
+		},
+		{
+			name: "tags, bom and crlf",
+			data: "\ufeff[ar:Muse]\r\n[ti:Uprising]\r\n\r\n[00:01]line",
+			want: []Line{{Time: ms(1000), Text: "line"}},
+		},
+		{
+			name: "pause",
+			data: "[00:01.00]line 1\n[00:02.00]\n[00:03.00]line 2",
+			want: []Line{
+				{Time: ms(1000), Text: "line 1"},
+				{Time: ms(2000), Text: ""},
+				{Time: ms(3000), Text: "line 2"},
+			},
+		},
+		{
+			name: "several timestamps",
+			data: "[00:01.00][00:05.00]chorus\n[00:07:50]line",
+			want: []Line{
+				{Time: ms(1000), Text: "chorus"},
+				{Time: ms(5000), Text: "chorus"},
+				{Time: ms(7500), Text: "line"},
+			},
+		},
+		{
+			name: "word timestamps and spaces",
+			data: "[00:01.00] <00:01.00>Ooh  <00:01.50>baby\t",
+			want: []Line{{Time: ms(1000), Text: "Ooh baby"}},
+		},
+		{
+			name: "positive offset shows lines earlier",
+			data: "[offset:+500]\n[00:00.20]line 1\n[00:01.00]line 2",
+			want: []Line{
+				{Time: 0, Text: "line 1"},
+				{Time: ms(500), Text: "line 2"},
+			},
+		},
+		{
+			name: "negative offset",
+			data: "[offset:-250]\n[00:01.00]line",
+			want: []Line{{Time: ms(1250), Text: "line"}},
+		},
+		{name: "empty", data: "[ar:Muse]\n\n", wantErr: ErrEmpty},
+		{name: "line without timestamp", data: "[00:01.00]line 1\nline 2", wantErr: ErrNoTimestamp},
+		{name: "seconds out of range", data: "[00:60.00]line", wantErr: ErrBadTimestamp},
+		{name: "too many timestamps", data: strings.Repeat("[00:01.00]", maxLineTimestamps+1) + "line", wantErr: ErrBadTimestamp},
+		{name: "not monotonic", data: "[00:02.00]line 1\n[00:01.00]line 2", wantErr: ErrNotMonotonic},
+		{name: "repeated timestamp", data: "[00:01.00][00:01.00]line", wantErr: ErrNotMonotonic},
+		{name: "invalid offset", data: "[offset:soon]\n[00:01.00]line", wantErr: ErrInvalidOffset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrorNamesLine(t *testing.T) {
+	_, err := Parse("[ti:Uprising]\n[00:01.00]line 1\nline 2")
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3: ") {
+		t.Fatalf("Parse() error = %v, want error of line 3", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	lines := []Line{
+		{Time: ms(1009), Text: "line 1"},
+		{Time: ms(62340), Text: ""},
+		{Time: ms(61*60000 + 5000), Text: "line 2"},
+	}
+
+	tests := []struct {
+		name string
+		tags Tags
+		want string
+	}{
+		{
+			name: "without tags",
+			want: "[00:01.00]line 1\n[01:02.34]\n[61:05.00]line 2\n",
+		},
+		{
+			name: "tags",
+			tags: Tags{Artist: "Muse", Album: "The Resistance"},
+			want: "[ar:Muse]\n[al:The Resistance]\n[00:01.00]line 1\n[01:02.34]\n[61:05.00]line 2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(lines, tt.tags); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	lines := []Line{
+		{Time: ms(1000), Text: "line 1"},
+		{Time: ms(2500), Text: ""},
+		{Time: ms(3990), Text: "line 2"},
+	}
+
+	got, err := Parse(Format(lines, Tags{Title: "Uprising"}))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, lines) {
+		t.Errorf("Parse(Format()) = %+v, want %+v", got, lines)
+	}
+}
+
+func TestText(t *testing.T) {
+	lines := []Line{{Text: "line 1"}, {Text: "line 2"}, {Text: ""}, {Text: "line 3"}}
+	if got, want := Text(lines), "line 1\nline 2\n\nline 3"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+// Package lyrics приводит текст песни к списку куплетов.
+// Куплеты разделяются пустыми строками, первая строка вида [Chorus] или [Припев 2] задает тип куплета.
+package lyrics
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	KindVerse  = "verse"
+	KindChorus = "chorus"
+	KindBridge = "bridge"
+)
+
+type Verse struct {
+	Kind string
+	Text string
+}
+
+var (
+	// экранированные переводы строк, в которых тексты приходят из API деталей песни
+	escapedNewlines = strings.NewReplacer(`\r\n`, "\n", `\n`, "\n", `\r`, "\n")
+	newlines        = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+	marker = regexp.MustCompile(`^\[\s*(?i:(verse|куплет|chorus|refrain|припев|bridge|бридж))\s*\d*\s*\]$`)
+)
+
+var markerKinds = map[string]string{
+	"verse":   KindVerse,
+	"куплет":  KindVerse,
+	"chorus":  KindChorus,
+	"refrain": KindChorus,
+	"припев":  KindChorus,
+	"bridge":  KindBridge,
+	"бридж":   KindBridge,
+}
+
+// Normalize раскрывает экранированные переводы строк, приводит концы строк к \n
+// и убирает пробелы в конце строк и пустые строки по краям текста
+func Normalize(text string) string {
+	lines := strings.Split(newlines.Replace(escapedNewlines.Replace(text)), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, isSpace)
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Parse разбивает текст на куплеты по пустым строкам
+func Parse(text string) []Verse {
+	verses := make([]Verse, 0)
+	for _, block := range strings.Split(Normalize(text), "\n\n") {
+		verse, ok := parseBlock(block)
+		if ok {
+			verses = append(verses, verse)
+		}
+	}
+
+	return verses
+}
+
+// IsVerse проверяет, что нормализованный текст - ровно один непустой куплет
+func IsVerse(text string) bool {
+	return text != "" && !strings.Contains(text, "\n\n")
+}
+
+func parseBlock(block string) (Verse, bool) {
+	// несколько пустых строк подряд оставляют в начале блока лишние переводы строк
+	block = strings.Trim(block, "\n")
+	if strings.TrimSpace(block) == "" {
+		return Verse{}, false
+	}
+
+	verse := Verse{Kind: KindVerse, Text: block}
+
+	first, rest, _ := strings.Cut(block, "\n")
+	if match := marker.FindStringSubmatch(strings.TrimSpace(first)); match != nil {
+		verse.Kind = markerKinds[strings.ToLower(match[1])]
+		verse.Text = strings.Trim(rest, "\n")
+	}
+
+	return verse, strings.TrimSpace(verse.Text) != ""
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
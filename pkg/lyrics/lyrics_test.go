@@ -0,0 +1,109 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "empty", text: "", want: ""},
+		{name: "escaped newlines", text: `line 1\nline 2\r\nline 3`, want: "line 1\nline 2\nline 3"},
+		{name: "crlf", text: "line 1\r\nline 2\rline 3", want: "line 1\nline 2\nline 3"},
+		{name: "trailing spaces", text: "line 1 \t\nline 2  ", want: "line 1\nline 2"},
+		{name: "leading spaces are kept", text: "  line 1", want: "  line 1"},
+		{name: "blank lines around text", text: "\n\n line \n\n\n", want: " line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.text); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Verse
+	}{
+		{name: "empty", text: " \n\n ", want: []Verse{}},
+		{
+			name: "verses without markers",
+			text: "Ooh baby\nOoh\n\nYou set my soul alight",
+			want: []Verse{
+				{Kind: KindVerse, Text: "Ooh baby\nOoh"},
+				{Kind: KindVerse, Text: "You set my soul alight"},
+			},
+		},
+		{
+			name: "markers",
+			text: "[Verse 1]\nline 1\n\n[ chorus ]\nline 2\n\n[Bridge]\nline 3\n\n[Refrain 2]\nline 4",
+			want: []Verse{
+				{Kind: KindVerse, Text: "line 1"},
+				{Kind: KindChorus, Text: "line 2"},
+				{Kind: KindBridge, Text: "line 3"},
+				{Kind: KindChorus, Text: "line 4"},
+			},
+		},
+		{
+			name: "russian markers",
+			text: "[Куплет 1]\nстрока 1\n\n[Припев]\nстрока 2\n\n[бридж]\nстрока 3",
+			want: []Verse{
+				{Kind: KindVerse, Text: "строка 1"},
+				{Kind: KindChorus, Text: "строка 2"},
+				{Kind: KindBridge, Text: "строка 3"},
+			},
+		},
+		{
+			name: "unknown marker is text",
+			text: "[Outro]\nline",
+			want: []Verse{{Kind: KindVerse, Text: "[Outro]\nline"}},
+		},
+		{
+			name: "marker without text is skipped",
+			text: "[Chorus]\n\nline",
+			want: []Verse{{Kind: KindVerse, Text: "line"}},
+		},
+		{
+			name: "several blank lines",
+			text: `line 1\n\n\n\nline 2`,
+			want: []Verse{
+				{Kind: KindVerse, Text: "line 1"},
+				{Kind: KindVerse, Text: "line 2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsVerse(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "", want: false},
+		{text: "line 1\nline 2", want: true},
+		{text: "line 1\n\nline 2", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsVerse(tt.text); got != tt.want {
+			t.Errorf("IsVerse(%q) = %t, want %t", tt.text, got, tt.want)
+		}
+	}
+}
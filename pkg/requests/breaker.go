@@ -0,0 +1,133 @@
+package requests
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker размыкается после failureThreshold неудач подряд и в течение openTimeout
+// отклоняет запросы, после чего пропускает один пробный запрос (half-open).
+// failureThreshold <= 0 отключает breaker.
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	failureThreshold int
+	openTimeout      time.Duration
+
+	state            BreakerState
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight bool
+
+	now func() time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            BreakerClosed,
+		now:              time.Now,
+	}
+}
+
+// State возвращает текущее состояние, учитывая истечение openTimeout
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		return BreakerHalfOpen
+	}
+
+	return b.state
+}
+
+// Allow возвращает ErrCircuitOpen, если запрос нужно отклонить без обращения к сервису
+func (b *CircuitBreaker) Allow() error {
+	if b.failureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+
+		b.state = BreakerHalfOpen
+		b.halfOpenInFlight = true
+		return nil
+	case BreakerHalfOpen:
+		if b.halfOpenInFlight {
+			return ErrCircuitOpen
+		}
+
+		b.halfOpenInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.halfOpenInFlight = false
+}
+
+// Release освобождает пробный запрос, завершившийся без результата (например, отмененный вызывающим):
+// состояние не меняется, следующий запрос в half-open станет новым пробным
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.halfOpenInFlight = false
+}
+
+func (b *CircuitBreaker) Failure() {
+	if b.failureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.halfOpenInFlight = false
+
+	if b.state == BreakerHalfOpen || b.failures >= b.failureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
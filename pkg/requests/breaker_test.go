@@ -0,0 +1,190 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestBreaker(threshold int, openTimeout time.Duration) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 11, 1, 12, 0, 0, 0, time.UTC)}
+	breaker := NewCircuitBreaker(threshold, openTimeout)
+	breaker.now = clock.Now
+	return breaker, clock
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	const openTimeout = time.Minute
+
+	// шаги: allow - Allow без ошибки, reject - Allow с ErrCircuitOpen, success, failure, release, wait - истечение openTimeout
+	tests := []struct {
+		name      string
+		threshold int
+		steps     []string
+		want      BreakerState
+	}{
+		{
+			name:      "stays closed below threshold",
+			threshold: 3,
+			steps:     []string{"allow", "failure", "allow", "failure", "allow"},
+			want:      BreakerClosed,
+		},
+		{
+			name:      "success resets failures",
+			threshold: 2,
+			steps:     []string{"failure", "success", "failure", "allow"},
+			want:      BreakerClosed,
+		},
+		{
+			name:      "opens at threshold",
+			threshold: 2,
+			steps:     []string{"failure", "failure", "reject"},
+			want:      BreakerOpen,
+		},
+		{
+			name:      "half-open after open timeout lets one probe",
+			threshold: 1,
+			steps:     []string{"failure", "wait", "allow", "reject"},
+			want:      BreakerHalfOpen,
+		},
+		{
+			name:      "successful probe closes",
+			threshold: 1,
+			steps:     []string{"failure", "wait", "allow", "success", "allow", "allow"},
+			want:      BreakerClosed,
+		},
+		{
+			name:      "failed probe opens again",
+			threshold: 3,
+			steps:     []string{"failure", "failure", "failure", "wait", "allow", "failure", "reject"},
+			want:      BreakerOpen,
+		},
+		{
+			name:      "released probe lets the next probe",
+			threshold: 1,
+			steps:     []string{"failure", "wait", "allow", "release", "allow", "reject"},
+			want:      BreakerHalfOpen,
+		},
+		{
+			name:      "disabled breaker never opens",
+			threshold: 0,
+			steps:     []string{"failure", "failure", "failure", "allow"},
+			want:      BreakerClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker, clock := newTestBreaker(tt.threshold, openTimeout)
+
+			for i, step := range tt.steps {
+				switch step {
+				case "allow":
+					if err := breaker.Allow(); err != nil {
+						t.Fatalf("step %d: Allow() = %v, want nil", i, err)
+					}
+				case "reject":
+					if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+						t.Fatalf("step %d: Allow() = %v, want ErrCircuitOpen", i, err)
+					}
+				case "success":
+					breaker.Success()
+				case "failure":
+					breaker.Failure()
+				case "release":
+					breaker.Release()
+				case "wait":
+					clock.now = clock.now.Add(openTimeout)
+				default:
+					t.Fatalf("unknown step %q", step)
+				}
+			}
+
+			if got := breaker.State(); got != tt.want {
+				t.Errorf("State() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientCanceledHalfOpenProbe(t *testing.T) {
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), ClientConfig{
+		BreakerFailureThreshold: 1,
+		BreakerOpenTimeout:      time.Minute,
+	})
+	clock := &fakeClock{now: time.Now()}
+	client.breaker.now = clock.Now
+
+	do := func(ctx context.Context, path string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := client.Do(req)
+		if err == nil {
+			res.Body.Close()
+		}
+
+		return res, err
+	}
+
+	if _, err := do(context.Background(), "/"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	if state := client.BreakerState(); state != BreakerOpen {
+		t.Fatalf("after failure state = %s, want open", state)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+
+	// пробный запрос half-open отменяется вызывающим
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := do(ctx, "/slow"); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("canceled probe: err = %v, want context error", err)
+	}
+
+	fail = false
+	res, err := do(context.Background(), "/")
+	if err != nil {
+		t.Fatalf("request after canceled probe: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("request after canceled probe: status %d, want 200", res.StatusCode)
+	}
+
+	if state := client.BreakerState(); state != BreakerClosed {
+		t.Errorf("after successful probe state = %s, want closed", state)
+	}
+}
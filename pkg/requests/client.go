@@ -0,0 +1,210 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type ClientConfig struct {
+	// таймаут одной попытки запроса, 0 - без таймаута
+	Timeout time.Duration
+	// количество повторов для идемпотентных запросов
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+}
+
+// Client - обертка над http.Client с таймаутом на попытку, повторами с экспоненциальной
+// задержкой и jitter для идемпотентных запросов и circuit breaker'ом
+type Client struct {
+	http    *http.Client
+	config  ClientConfig
+	breaker *CircuitBreaker
+}
+
+func NewClient(httpClient *http.Client, config ClientConfig) *Client {
+	return &Client{
+		http:    httpClient,
+		config:  config,
+		breaker: NewCircuitBreaker(config.BreakerFailureThreshold, config.BreakerOpenTimeout),
+	}
+}
+
+func (c *Client) BreakerState() BreakerState {
+	return c.breaker.State()
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	retryable := isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		if err := c.breaker.Allow(); err != nil {
+			return nil, err
+		}
+
+		attemptReq, err := c.prepareAttempt(req, attempt)
+		if err != nil {
+			c.breaker.Release()
+			return nil, err
+		}
+
+		res, err := c.doAttempt(attemptReq)
+		switch {
+		case err != nil && req.Context().Err() != nil:
+			// запрос отменен вызывающим, это не отказ сервиса; пробный запрос half-open освобождается
+			c.breaker.Release()
+		case err != nil || res.StatusCode >= http.StatusInternalServerError:
+			c.breaker.Failure()
+		default:
+			c.breaker.Success()
+		}
+
+		if !retryable || attempt >= c.config.MaxRetries || !shouldRetry(req.Context(), res, err) {
+			return res, err
+		}
+
+		delay := c.backoff(attempt)
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				if c.config.RetryMaxDelay > 0 && retryAfter > c.config.RetryMaxDelay {
+					return res, err
+				}
+				delay = retryAfter
+			}
+
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) prepareAttempt(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+func (c *Client) doAttempt(req *http.Request) (*http.Response, error) {
+	if c.config.Timeout <= 0 {
+		return c.http.Do(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), c.config.Timeout)
+	res, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// контекст попытки должен жить, пока вызывающий читает тело ответа
+	res.Body = &cancelOnCloseBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// backoff возвращает задержку перед повтором: случайное значение в [0, base*2^attempt] (full jitter)
+func (c *Client) backoff(attempt int) time.Duration {
+	if c.config.RetryBaseDelay <= 0 {
+		return 0
+	}
+
+	delay := c.config.RetryBaseDelay << attempt
+	if delay <= 0 || (c.config.RetryMaxDelay > 0 && delay > c.config.RetryMaxDelay) {
+		delay = c.config.RetryMaxDelay
+	}
+
+	return rand.N(delay + 1)
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get("Idempotency-Key") != ""
+	}
+}
+
+func shouldRetry(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter поддерживает оба формата заголовка: delay-seconds и HTTP-date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
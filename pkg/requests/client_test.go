@@ -0,0 +1,232 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		idempotency  bool
+		statuses     []int
+		wantStatus   int
+		wantAttempts int32
+	}{
+		{
+			name:         "get is retried on 5xx",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+		},
+		{
+			name:         "get is retried on 429",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "retries are limited",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusInternalServerError},
+			wantStatus:   http.StatusInternalServerError,
+			wantAttempts: 3,
+		},
+		{
+			name:         "4xx is not retried",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusNotFound, http.StatusOK},
+			wantStatus:   http.StatusNotFound,
+			wantAttempts: 1,
+		},
+		{
+			name:         "post is not retried",
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 1,
+		},
+		{
+			name:         "post with idempotency key is retried",
+			method:       http.MethodPost,
+			idempotency:  true,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := int(attempts.Add(1)) - 1
+				w.WriteHeader(tt.statuses[min(attempt, len(tt.statuses)-1)])
+			}))
+			defer server.Close()
+
+			client := NewClient(server.Client(), ClientConfig{MaxRetries: 2})
+
+			req, err := http.NewRequest(tt.method, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.idempotency {
+				req.Header.Set("Idempotency-Key", "key")
+			}
+
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestClientAttemptTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), ClientConfig{Timeout: 50 * time.Millisecond, MaxRetries: 1})
+
+	res, err := Get[[]byte](context.Background(), client, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", res.StatusCode)
+	}
+
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestClientAttemptTimeoutWithoutRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), ClientConfig{Timeout: 50 * time.Millisecond})
+
+	_, err := Get[[]byte](context.Background(), client, server.URL, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{name: "empty", value: "", wantOk: false},
+		{name: "seconds", value: "3", want: 3 * time.Second, wantOk: true},
+		{name: "zero seconds", value: "0", want: 0, wantOk: true},
+		{name: "negative seconds", value: "-1", wantOk: false},
+		{name: "date in the past", value: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOk: true},
+		{name: "garbage", value: "soon", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		accepted   []int
+		want       string
+		wantStatus int
+		wantErr    bool
+	}{
+		{name: "decodes json", status: http.StatusOK, body: `{"name":"song"}`, want: "song"},
+		{name: "empty body", status: http.StatusNoContent, body: "", want: ""},
+		{name: "invalid json", status: http.StatusOK, body: `{"name":`, wantErr: true},
+		{name: "non 2xx is http error", status: http.StatusNotFound, body: "not found", wantStatus: http.StatusNotFound, wantErr: true},
+		{
+			name:       "status outside accepted",
+			status:     http.StatusCreated,
+			body:       `{"name":"song"}`,
+			accepted:   []int{http.StatusOK},
+			wantStatus: http.StatusCreated,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query()["q"]; len(got) != 2 || got[0] != "a" || got[1] != "b" {
+					t.Errorf("query q = %v, want [a b]", got)
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			res, err := Get[payload](context.Background(), server.Client(), server.URL+"?q=a", &Options{
+				Query:            map[string][]string{"q": {"b"}},
+				AcceptedStatuses: tt.accepted,
+			})
+
+			var httpErr *HTTPError
+			if tt.wantStatus != 0 {
+				if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.wantStatus || string(httpErr.Body) != tt.body {
+					t.Fatalf("Get() error = %v, want HTTPError with status %d", err, tt.wantStatus)
+				}
+				return
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %t", err, tt.wantErr)
+			}
+
+			if err == nil && res.Body.Name != tt.want {
+				t.Errorf("Body.Name = %q, want %q", res.Body.Name, tt.want)
+			}
+		})
+	}
+}
@@ -4,46 +4,162 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 )
 
-func RequestWithJSON[T any, R any](ctx context.Context, client *http.Client, url string, body T, headers map[string]string) (R, error) { //nolint:ireturn,lll
-	var result R
+type Options struct {
+	// query-параметры, добавляются к параметрам, уже присутствующим в url
+	Query   url.Values
+	Headers map[string]string
+	// статусы, считающиеся успешными; по умолчанию любой 2xx
+	AcceptedStatuses []int
+}
+
+type Response[R any] struct {
+	StatusCode int
+	Header     http.Header
+	Body       R
+}
+
+// HTTPError возвращается, когда сервис ответил статусом не из списка допустимых
+type HTTPError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+func Get[R any](ctx context.Context, client Doer, url string, opts *Options) (*Response[R], error) {
+	return Do[R](ctx, client, http.MethodGet, url, nil, opts)
+}
+
+func Post[R any](ctx context.Context, client Doer, url string, body any, opts *Options) (*Response[R], error) {
+	return Do[R](ctx, client, http.MethodPost, url, body, opts)
+}
 
-	bodyData, err := json.Marshal(body)
+func Put[R any](ctx context.Context, client Doer, url string, body any, opts *Options) (*Response[R], error) {
+	return Do[R](ctx, client, http.MethodPut, url, body, opts)
+}
+
+func Patch[R any](ctx context.Context, client Doer, url string, body any, opts *Options) (*Response[R], error) {
+	return Do[R](ctx, client, http.MethodPatch, url, body, opts)
+}
+
+func Delete[R any](ctx context.Context, client Doer, url string, opts *Options) (*Response[R], error) {
+	return Do[R](ctx, client, http.MethodDelete, url, nil, opts)
+}
+
+// Do отправляет запрос с JSON-телом (nil - без тела) и декодирует JSON-ответ в R.
+// Пустое тело ответа (например, 204) оставляет R нулевым, R = []byte получает тело как есть.
+func Do[R any](ctx context.Context, client Doer, method string, rawUrl string, body any, opts *Options) (*Response[R], error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	reqUrl, err := buildUrl(rawUrl, opts.Query)
 	if err != nil {
-		return result, errors.New("invalid request body")
+		return nil, err
 	}
 
-	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(bodyData))
+	var reqBody io.Reader
+	if body != nil {
+		bodyData, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+		reqBody = bytes.NewReader(bodyData)
+	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create a request: %w", err)
+	}
 
-	for k, v := range headers {
+	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for k, v := range opts.Headers {
 		req.Header.Set(k, v)
 	}
 
 	res, err := client.Do(req)
 	if err != nil {
-		return result, fmt.Errorf("cannot send a request: %w", err)
+		return nil, fmt.Errorf("cannot send a request: %w", err)
 	}
 	defer res.Body.Close()
 
 	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
-		return result, fmt.Errorf("cannot read a response from service: %w", err)
+		return nil, fmt.Errorf("cannot read a response from service: %w", err)
+	}
+
+	if !isAccepted(res.StatusCode, opts.AcceptedStatuses) {
+		return nil, &HTTPError{
+			Method:     method,
+			URL:        reqUrl,
+			StatusCode: res.StatusCode,
+			Header:     res.Header,
+			Body:       resBody,
+		}
+	}
+
+	result := &Response[R]{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return result, fmt.Errorf("%w: %s", errors.New("invalid response from service"), resBody)
+	if raw, ok := any(&result.Body).(*[]byte); ok {
+		*raw = resBody
+		return result, nil
 	}
 
-	if err := json.Unmarshal(resBody, &result); err != nil {
-		return result, fmt.Errorf("cannot parse a response from service: %w", err)
+	if len(bytes.TrimSpace(resBody)) == 0 {
+		return result, nil
+	}
+
+	if err := json.Unmarshal(resBody, &result.Body); err != nil {
+		return nil, fmt.Errorf("cannot parse a response from service: %w", err)
 	}
 
 	return result, nil
 }
+
+func buildUrl(rawUrl string, query url.Values) (string, error) {
+	if len(query) == 0 {
+		return rawUrl, nil
+	}
+
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+
+	values := parsed.Query()
+	for k, vs := range query {
+		for _, v := range vs {
+			values.Add(k, v)
+		}
+	}
+	parsed.RawQuery = values.Encode()
+
+	return parsed.String(), nil
+}
+
+func isAccepted(status int, accepted []int) bool {
+	if len(accepted) == 0 {
+		return status >= http.StatusOK && status < http.StatusMultipleChoices
+	}
+
+	return slices.Contains(accepted, status)
+}
@@ -0,0 +1,59 @@
+package textdiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []Line
+	}{
+		{name: "both empty", want: []Line{}},
+		{
+			name: "equal",
+			old:  "line 1\nline 2",
+			new:  "line 1\nline 2",
+			want: []Line{{OpEqual, "line 1"}, {OpEqual, "line 2"}},
+		},
+		{
+			name: "from empty",
+			new:  "line 1\nline 2",
+			want: []Line{{OpInsert, "line 1"}, {OpInsert, "line 2"}},
+		},
+		{
+			name: "to empty",
+			old:  "line 1",
+			want: []Line{{OpDelete, "line 1"}},
+		},
+		{
+			name: "changed line",
+			old:  "line 1\nline 2\nline 3",
+			new:  "line 1\nline two\nline 3",
+			want: []Line{{OpEqual, "line 1"}, {OpDelete, "line 2"}, {OpInsert, "line two"}, {OpEqual, "line 3"}},
+		},
+		{
+			name: "inserted and deleted lines",
+			old:  "a\nb\nc\nd",
+			new:  "b\nc\ne\nd",
+			want: []Line{{OpDelete, "a"}, {OpEqual, "b"}, {OpEqual, "c"}, {OpInsert, "e"}, {OpEqual, "d"}},
+		},
+		{
+			name: "empty line is a line",
+			old:  "verse 1\n\nverse 2",
+			new:  "verse 1\nverse 2",
+			want: []Line{{OpEqual, "verse 1"}, {OpDelete, ""}, {OpEqual, "verse 2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
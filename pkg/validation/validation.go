@@ -0,0 +1,155 @@
+// Package validation проверяет поля входных данных декларативными правилами и собирает
+// все нарушения в один список, чтобы клиент мог показать ошибку у каждого поля
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// коды нарушений, по ним клиент может выбрать свое сообщение
+const (
+	CodeRequired        = "required"
+	CodeTooLong         = "too_long"
+	CodeInvalidURL      = "invalid_url"
+	CodeInvalidDate     = "invalid_date"
+	CodeFutureDate      = "future_date"
+	CodeInvalidLanguage = "invalid_language"
+)
+
+// FieldError - нарушение правила в поле
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors - нарушения правил во всех проверенных полях, по одному на поле
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Rule проверяет значение поля и возвращает нарушение или nil. Field нарушения заполняет Field
+type Rule[T any] func(value T) *FieldError
+
+// Check - проверка одного поля, собирается через Field
+type Check func() *FieldError
+
+// Field проверяет значение правилами по порядку до первого нарушения
+func Field[T any](name string, value T, rules ...Rule[T]) Check {
+	return func() *FieldError {
+		for _, rule := range rules {
+			if fe := rule(value); fe != nil {
+				fe.Field = name
+				return fe
+			}
+		}
+
+		return nil
+	}
+}
+
+// Validate выполняет все проверки и возвращает Errors, если хотя бы одно поле нарушает правила
+func Validate(checks ...Check) error {
+	var errs Errors
+	for _, check := range checks {
+		if fe := check(); fe != nil {
+			errs = append(errs, fe)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Required - строка не пустая и не состоит из одних пробелов
+func Required(value string) *FieldError {
+	if strings.TrimSpace(value) == "" {
+		return &FieldError{Code: CodeRequired, Message: "is required"}
+	}
+
+	return nil
+}
+
+// MaxLength - строка не длиннее max символов
+func MaxLength(max int) Rule[string] {
+	return func(value string) *FieldError {
+		if utf8.RuneCountInString(value) > max {
+			return &FieldError{Code: CodeTooLong, Message: fmt.Sprintf("must be at most %d characters", max)}
+		}
+
+		return nil
+	}
+}
+
+// URL - пустая строка или абсолютная http(s) ссылка
+func URL(value string) *FieldError {
+	if value == "" {
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &FieldError{Code: CodeInvalidURL, Message: "must be an absolute http or https URL"}
+	}
+
+	return nil
+}
+
+// Date - пустая строка или дата YYYY-MM-DD
+func Date(value string) *FieldError {
+	if value == "" {
+		return nil
+	}
+
+	if _, err := time.Parse(time.DateOnly, value); err != nil {
+		return &FieldError{Code: CodeInvalidDate, Message: "must be a date in YYYY-MM-DD format"}
+	}
+
+	return nil
+}
+
+// NotFutureDate - пустая строка, некорректная дата (ее проверяет Date) или дата не позже сегодняшней.
+// Сегодня считается по самому восточному часовому поясу, чтобы дата выпуска "сегодня" подходила везде
+func NotFutureDate(value string) *FieldError {
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil
+	}
+
+	today := time.Now().In(time.FixedZone("UTC+14", 14*60*60)).Format(time.DateOnly)
+	if date.Format(time.DateOnly) > today {
+		return &FieldError{Code: CodeFutureDate, Message: "must not be in the future"}
+	}
+
+	return nil
+}
+
+// Language - пустая строка или тег языка BCP 47
+func Language(value string) *FieldError {
+	if value == "" {
+		return nil
+	}
+
+	tag, err := language.Parse(value)
+	if err != nil || tag == language.Und {
+		return &FieldError{Code: CodeInvalidLanguage, Message: "must be a BCP 47 language tag"}
+	}
+
+	return nil
+}
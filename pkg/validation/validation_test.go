@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRules(t *testing.T) {
+	future := time.Now().UTC().AddDate(0, 0, 2).Format(time.DateOnly)
+
+	tests := []struct {
+		name  string
+		rule  Rule[string]
+		value string
+		want  string
+	}{
+		{name: "required", rule: Required, value: "Uprising"},
+		{name: "required empty", rule: Required, value: "", want: CodeRequired},
+		{name: "required spaces", rule: Required, value: " \t\n", want: CodeRequired},
+		{name: "max length", rule: MaxLength(5), value: "Кино!"},
+		{name: "max length exceeded", rule: MaxLength(5), value: "Кино!!", want: CodeTooLong},
+		{name: "url empty", rule: URL, value: ""},
+		{name: "url https", rule: URL, value: "https://example.com/song?id=1"},
+		{name: "url http", rule: URL, value: "http://example.com"},
+		{name: "url other scheme", rule: URL, value: "ftp://example.com", want: CodeInvalidURL},
+		{name: "url relative", rule: URL, value: "/songs/1", want: CodeInvalidURL},
+		{name: "url without host", rule: URL, value: "https://", want: CodeInvalidURL},
+		{name: "date empty", rule: Date, value: ""},
+		{name: "date", rule: Date, value: "2006-07-16"},
+		{name: "date api format", rule: Date, value: "16.07.2006", want: CodeInvalidDate},
+		{name: "date impossible", rule: Date, value: "2006-02-31", want: CodeInvalidDate},
+		{name: "not future date past", rule: NotFutureDate, value: "2006-07-16"},
+		{name: "not future date invalid", rule: NotFutureDate, value: "soon"},
+		{name: "not future date future", rule: NotFutureDate, value: future, want: CodeFutureDate},
+		{name: "language empty", rule: Language, value: ""},
+		{name: "language", rule: Language, value: "en"},
+		{name: "language with region", rule: Language, value: "pt-BR"},
+		{name: "language undefined", rule: Language, value: "und", want: CodeInvalidLanguage},
+		{name: "language invalid", rule: Language, value: "not a language", want: CodeInvalidLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fe := tt.rule(tt.value)
+
+			got := ""
+			if fe != nil {
+				got = fe.Code
+			}
+
+			if got != tt.want {
+				t.Errorf("rule(%q) code = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   []string
+	}{
+		{
+			name: "valid",
+			checks: []Check{
+				Field("song", "Uprising", Required, MaxLength(255)),
+				Field("link", "https://example.com", URL),
+			},
+		},
+		{
+			name: "all fields are reported",
+			checks: []Check{
+				Field("song", "", Required, MaxLength(255)),
+				Field("group", "Muse", Required),
+				Field("link", "example.com", URL),
+			},
+			want: []string{"song:" + CodeRequired, "link:" + CodeInvalidURL},
+		},
+		{
+			name: "first violated rule of a field",
+			checks: []Check{
+				Field("release_date", "2006-13-01", Date, NotFutureDate),
+				Field("song", strings.Repeat("a", 10), Required, MaxLength(5), URL),
+			},
+			want: []string{"release_date:" + CodeInvalidDate, "song:" + CodeTooLong},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.checks...)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() error = %v, want Errors", err)
+			}
+
+			got := make([]string, 0, len(errs))
+			for _, fe := range errs {
+				got = append(got, fe.Field+":"+fe.Code)
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}